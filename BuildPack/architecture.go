@@ -0,0 +1,33 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// DefaultArchitecture is the architecture of dependencies that do not declare
+// one in buildpack.toml, which only listed linux_x64 artifacts before
+// dependencies had an arch.
+const DefaultArchitecture = "amd64"
+
+// TargetArchitecture returns the architecture to install dependencies for:
+// the architecture the buildpack runs on, unless $BP_DOTNET_ASPNET_ARCH
+// overrides it for a cross-build. Architectures are named as Go names them,
+// though the .NET and kernel names x64, x86_64 and aarch64 are accepted too.
+func TargetArchitecture() (string, error) {
+	arch, ok := os.LookupEnv("BP_DOTNET_ASPNET_ARCH")
+	if !ok {
+		return runtime.GOARCH, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(arch)) {
+	case "amd64", "x64", "x86_64":
+		return "amd64", nil
+	case "arm64", "aarch64":
+		return "arm64", nil
+	default:
+		return "", fmt.Errorf("unsupported $BP_DOTNET_ASPNET_ARCH %q: must be one of amd64 or arm64", arch)
+	}
+}
@@ -1,9 +1,12 @@
 package dotnetcoreaspnet
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver"
@@ -12,6 +15,12 @@ import (
 	"github.com/paketo-buildpacks/packit/postal"
 )
 
+// LayerFormatVersion is the version of the layout of the dotnet-core-aspnet
+// layer and of the environment it exports. It changes whenever a release of
+// the buildpack changes either, so that layers cached by an earlier release
+// are reinstalled rather than reused.
+const LayerFormatVersion = "1"
+
 //go:generate faux --interface EntryResolver --output fakes/entry_resolver.go
 type EntryResolver interface {
 	Resolve(string, []packit.BuildpackPlanEntry, []interface{}) (packit.BuildpackPlanEntry, []packit.BuildpackPlanEntry)
@@ -20,21 +29,100 @@ type EntryResolver interface {
 
 //go:generate faux --interface DependencyManager --output fakes/dependency_manager.go
 type DependencyManager interface {
-	Resolve(path, id, version, stack string) (postal.Dependency, error)
+	Resolve(path, id, version, stack, arch string) (postal.Dependency, error)
 	Install(dependency postal.Dependency, cnbPath, layerPath string) error
 	GenerateBillOfMaterials(dependencies ...postal.Dependency) []packit.BOMEntry
 }
 
+//go:generate faux --interface VersionResolver --output fakes/version_resolver.go
+type VersionResolver interface {
+	Resolve(path, id, version, policy, stack, arch string) (string, error)
+}
+
+//go:generate faux --interface AliasResolver --output fakes/alias_resolver.go
+type AliasResolver interface {
+	Resolve(path, id, alias, stack, arch string, allowPrerelease bool) (string, error)
+}
+
+//go:generate faux --interface ConstraintResolver --output fakes/constraint_resolver.go
+type ConstraintResolver interface {
+	Resolve(path, id, constraint, stack, arch string) (string, error)
+}
+
+//go:generate faux --interface LockfileManager --output fakes/lockfile_manager.go
+type LockfileManager interface {
+	Read(path string) (Lock, error)
+	Write(path string, lock Lock) error
+}
+
+//go:generate faux --interface CompatibilityChecker --output fakes/compatibility_checker.go
+type CompatibilityChecker interface {
+	Check(layerPath, dotnetRoot string) error
+}
+
+//go:generate faux --interface MappingResolver --output fakes/mapping_resolver.go
+type MappingResolver interface {
+	FindDependencyMapping(sha256, platformDir string) (DependencyMapping, error)
+}
+
+//go:generate faux --interface MirrorResolver --output fakes/mirror_resolver.go
+type MirrorResolver interface {
+	FindDependencyMirror(uri, platformDir string) (string, error)
+}
+
+//go:generate faux --interface DownloadFetcher --output fakes/download_fetcher.go
+type DownloadFetcher interface {
+	Fetch(dependency postal.Dependency, cnbPath, cachePath string) (CachedDownload, error)
+}
+
+//go:generate faux --interface LayerVerifier --output fakes/layer_verifier.go
+type LayerVerifier interface {
+	Record(layerPath string) (map[string]interface{}, error)
+	Verify(layerPath string, manifest map[string]interface{}) error
+}
+
 //go:generate faux --interface Symlinker --output fakes/symlinker.go
 type Symlinker interface {
 	Link(workingDir, layerPath string) (Err error)
 }
 
-func Build(entries EntryResolver, dependencies DependencyManager, symlinker Symlinker, logger LogEmitter, clock chronos.Clock) packit.BuildFunc {
+func Build(entries EntryResolver, dependencies DependencyManager, versionResolver VersionResolver, aliasResolver AliasResolver, prereleaseResolver ConstraintResolver, lockfile LockfileManager, compatibilityChecker CompatibilityChecker, mappingResolver MappingResolver, mirrorResolver MirrorResolver, downloads DownloadFetcher, layerVerifier LayerVerifier, symlinker Symlinker, logger LogEmitter, clock chronos.Clock) packit.BuildFunc {
 	return func(context packit.BuildContext) (packit.BuildResult, error) {
 		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
+
+		// the download cache is a cache-only layer, which is only kept if it is
+		// returned from every build, including those that do not download
+		downloadsLayer, err := context.Layers.Get("dotnet-core-aspnet-downloads")
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		downloadsLayer.Cache = true
+
+		for _, entry := range context.Plan.Entries {
+			if selfContained, _ := entry.Metadata["self-contained"].(bool); selfContained {
+				logger.Process("Skipping Dotnet Core ASPNet installation")
+				logger.Subprocess("The app is self-contained and ships its own ASP.NET Core runtime")
+				logger.Break()
+
+				return packit.BuildResult{
+					Layers: []packit.Layer{downloadsLayer},
+				}, nil
+			}
+		}
+
 		logger.Process("Resolving Dotnet Core ASPNet version")
 
+		arch, err := TargetArchitecture()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if _, ok := os.LookupEnv("BP_DOTNET_ASPNET_ARCH"); ok {
+			logger.Subprocess("Target architecture (from $BP_DOTNET_ASPNET_ARCH): %s", arch)
+			logger.Break()
+		}
+
 		if v, ok := os.LookupEnv("RUNTIME_VERSION"); ok {
 			context.Plan.Entries = append(context.Plan.Entries, packit.BuildpackPlanEntry{
 				Name: "dotnet-aspnetcore",
@@ -45,17 +133,41 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 			})
 		}
 
-		priorities := []interface{}{
-			"RUNTIME_VERSION",
-			"BP_DOTNET_FRAMEWORK_VERSION",
-			"buildpack.yml",
-			regexp.MustCompile(`.*\.(cs)|(fs)|(vb)proj`),
-			"runtimeconfig.json",
+		priority, listed, err := LoadVersionSourcePriority()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if listed > 0 {
+			logger.Subprocess("Version source priority (from $BP_DOTNET_VERSION_SOURCE_PRIORITY): %s", strings.Join(priority, ", "))
+			if listed < len(priority) {
+				logger.Action("Unlisted sources follow in their default order: %s", strings.Join(priority[listed:], ", "))
+			}
+		} else {
+			logger.Subprocess("Version source priority (default): %s", strings.Join(priority, ", "))
 		}
+		logger.Break()
 
-		entry, sortedEntries := entries.Resolve("dotnet-aspnetcore", context.Plan.Entries, priorities)
+		entry, sortedEntries := entries.Resolve("dotnet-aspnetcore", context.Plan.Entries, priority.Priorities())
 		logger.Candidates(sortedEntries)
 
+		var strictVersions bool
+		if value, ok := os.LookupEnv("BP_DOTNET_ASPNET_STRICT_VERSIONS"); ok {
+			var err error
+			strictVersions, err = strconv.ParseBool(value)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to parse $BP_DOTNET_ASPNET_STRICT_VERSIONS: %w", err)
+			}
+		}
+
+		if conflicts := FindVersionConflicts(sortedEntries); conflicts != nil {
+			if strictVersions {
+				return packit.BuildResult{}, fmt.Errorf("version sources require conflicting %s versions: %s", entry.Name, describeVersionSources(conflicts))
+			}
+
+			logger.VersionConflicts(entry, conflicts)
+		}
+
 		version, _ := entry.Metadata["version"].(string)
 
 		source, _ := entry.Metadata["version-source"].(string)
@@ -66,19 +178,158 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 			logger.Break()
 		}
 
-		dependency, err := dependencies.Resolve(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, context.Stack)
+		// when locking, a version recorded by an earlier build takes precedence
+		// over every version source
+		var locking bool
+		if value, ok := os.LookupEnv("BP_DOTNET_ASPNET_LOCK"); ok {
+			var err error
+			locking, err = strconv.ParseBool(value)
+			if err != nil {
+				return packit.BuildResult{}, fmt.Errorf("failed to parse $BP_DOTNET_ASPNET_LOCK: %w", err)
+			}
+		}
+
+		var lock Lock
+		if locking {
+			var err error
+			lock, err = lockfile.Read(filepath.Join(context.WorkingDir, LockfileName))
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		if lock.Version != "" {
+			if lock.Stack != context.Stack {
+				return packit.BuildResult{}, fmt.Errorf("%s locks %s version %s for the %q stack, but the app is being built on the %q stack: remove %s to select a new version", LockfileName, lock.ID, lock.Version, lock.Stack, context.Stack, LockfileName)
+			}
+
+			// lockfiles written before dependencies had an architecture lock the
+			// only architecture there was
+			lockedArch := lock.Arch
+			if lockedArch == "" {
+				lockedArch = DefaultArchitecture
+			}
+
+			if lockedArch != arch {
+				return packit.BuildResult{}, fmt.Errorf("%s locks %s version %s for the %s architecture, but the app is being built for the %s architecture: remove %s to select a new version", LockfileName, lock.ID, lock.Version, lockedArch, arch, LockfileName)
+			}
+
+			entry.Metadata = map[string]interface{}{
+				"version-source": LockfileName,
+				"version":        lock.Version,
+			}
+			source, version = LockfileName, lock.Version
+		} else {
+			// pre-releases are only selected when requested exactly, unless they
+			// are allowed to satisfy version constraints too
+			var allowPrerelease bool
+			if value, ok := os.LookupEnv("BP_DOTNET_ALLOW_PRERELEASE"); ok {
+				var err error
+				allowPrerelease, err = strconv.ParseBool(value)
+				if err != nil {
+					return packit.BuildResult{}, fmt.Errorf("failed to parse $BP_DOTNET_ALLOW_PRERELEASE: %w", err)
+				}
+			}
+
+			rollForward := RollForwardPolicy(entry)
+
+			resolved := version
+			var err error
+			switch {
+			case IsVersionAlias(version):
+				resolved, err = aliasResolver.Resolve(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, context.Stack, arch, allowPrerelease)
+
+			case rollForward != "":
+				logger.Subprocess("Applying %s roll-forward policy to version %s", rollForward, version)
+				resolved, err = versionResolver.Resolve(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, rollForward, context.Stack, arch)
+
+			case allowPrerelease && version != "":
+				resolved, err = prereleaseResolver.Resolve(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, context.Stack, arch)
+			}
+
+			if err != nil {
+				err = newResolutionError(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, source, context.Stack, arch, err)
+				logger.ResolutionError(err)
+
+				return packit.BuildResult{}, err
+			}
+
+			version = resolved
+		}
+
+		dependency, err := dependencies.Resolve(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, context.Stack, arch)
 		if err != nil {
+			err = newResolutionError(filepath.Join(context.CNBPath, "buildpack.toml"), entry.Name, version, source, context.Stack, arch, err)
+			logger.ResolutionError(err)
+
+			if lock.Version != "" {
+				return packit.BuildResult{}, fmt.Errorf("%s version %s locked by %s is no longer available: %w", lock.ID, lock.Version, LockfileName, err)
+			}
 			return packit.BuildResult{}, err
 		}
 
+		if lock.Version != "" && dependency.SHA256 != lock.SHA256 {
+			return packit.BuildResult{}, fmt.Errorf("%s version %s locked by %s is no longer available: buildpack.toml has SHA256 %s instead of %s", lock.ID, lock.Version, LockfileName, dependency.SHA256, lock.SHA256)
+		}
+
 		logger.SelectedDependency(entry, dependency, clock.Now())
 
+		deprecationPolicy, err := LoadDeprecationPolicy()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if deprecationPolicy.Action != DeprecationPolicyIgnore {
+			logger.Deprecation(dependency, clock.Now(), deprecationPolicy.Window)
+		}
+
+		if deprecationPolicy.Action == DeprecationPolicyFail && !dependency.DeprecationDate.IsZero() && !dependency.DeprecationDate.After(clock.Now()) {
+			err = newDeprecatedVersionError(filepath.Join(context.CNBPath, "buildpack.toml"), source, context.Stack, arch, dependency, clock.Now())
+			logger.ResolutionError(err)
+
+			return packit.BuildResult{}, err
+		}
+
+		if locking && lock.Version == "" {
+			err = lockfile.Write(filepath.Join(context.WorkingDir, LockfileName), Lock{
+				ID:      dependency.ID,
+				Version: dependency.Version,
+				SHA256:  dependency.SHA256,
+				Stack:   context.Stack,
+				Arch:    arch,
+			})
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			logger.Subprocess("Locked %s version %s in %s", dependency.ID, dependency.Version, LockfileName)
+			logger.Subprocess("Commit %s to the app source to select this version in later builds.", LockfileName)
+			logger.Break()
+		}
+
+		// the dotnet-runtime buildpack links Microsoft.NETCore.App into the same
+		// DOTNET_ROOT as this buildpack
+		dotnetRoot := filepath.Join(context.WorkingDir, ".dotnet_root")
+
 		aspNetLayer, err := context.Layers.Get("dotnet-core-aspnet")
 		if err != nil {
 			return packit.BuildResult{}, err
 		}
 
-		bom := dependencies.GenerateBillOfMaterials(dependency)
+		mapping, err := mappingResolver.FindDependencyMapping(dependency.SHA256, context.Platform.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		// a substitute that is a different build of the dependency is what the
+		// layer holds, so it is what the bill of materials lists
+		installed := dependency
+		if mapping.URI != "" && mapping.SHA256 != "" {
+			installed.URI = RedactCredentials(mapping.URI)
+			installed.SHA256 = mapping.SHA256
+		}
+
+		bom := dependencies.GenerateBillOfMaterials(installed)
 		launch, build := entries.MergeLayerTypes("dotnet-aspnetcore", context.Plan.Entries)
 
 		var buildMetadata packit.BuildMetadata
@@ -91,11 +342,58 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 			launchMetadata.BOM = bom
 		}
 
-		cachedSHA, ok := aspNetLayer.Metadata["dependency-sha"].(string)
-		if ok && cachedSHA == dependency.SHA256 {
+		// a layer is only reused if it was installed from the same dependency,
+		// or substitute, for the same stack and architecture by a buildpack
+		// that lays it out the same way; and a layer that does not match its
+		// content manifest any more is reinstalled
+		cacheKey := []struct{ field, value string }{
+			{"dependency-sha", dependency.SHA256},
+			{"dependency-mapping", RedactCredentials(mapping.URI)},
+			{"stack", context.Stack},
+			{"arch", arch},
+			{"layer-format", LayerFormatVersion},
+		}
+
+		_, reusable := aspNetLayer.Metadata["dependency-sha"].(string)
+		if reusable {
+			for _, key := range cacheKey {
+				cached, _ := aspNetLayer.Metadata[key.field].(string)
+				if cached != key.value {
+					logger.Process("Reinstalling cached layer %s", aspNetLayer.Path)
+					logger.Subprocess("The layer %s changed from %q to %q", key.field, cached, key.value)
+					logger.Break()
+
+					reusable = false
+					break
+				}
+			}
+		}
+
+		if reusable {
+			manifest, _ := aspNetLayer.Metadata["content-manifest"].(map[string]interface{})
+			err = layerVerifier.Verify(aspNetLayer.Path, manifest)
+			if err != nil {
+				if !errors.As(err, &CorruptLayerError{}) {
+					return packit.BuildResult{}, err
+				}
+
+				logger.Process("Reinstalling cached layer %s", aspNetLayer.Path)
+				logger.Subprocess("The layer failed verification: %s", err)
+				logger.Break()
+
+				reusable = false
+			}
+		}
+
+		if reusable {
 			logger.Process("Reusing cached layer %s", aspNetLayer.Path)
 			logger.Break()
 
+			err = compatibilityChecker.Check(aspNetLayer.Path, dotnetRoot)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
 			err = symlinker.Link(context.WorkingDir, aspNetLayer.Path)
 			if err != nil {
 				return packit.BuildResult{}, err
@@ -104,7 +402,7 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 			aspNetLayer.Launch, aspNetLayer.Build, aspNetLayer.Cache = launch, build, build
 
 			return packit.BuildResult{
-				Layers: []packit.Layer{aspNetLayer},
+				Layers: []packit.Layer{aspNetLayer, downloadsLayer},
 				Build:  buildMetadata,
 				Launch: launchMetadata,
 			}, nil
@@ -118,9 +416,58 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 
 		aspNetLayer.Launch, aspNetLayer.Build, aspNetLayer.Cache = launch, build, launch || build
 
+		// the dependency is downloaded from a substitute or a mirror, if there
+		// is one, but is still verified against the SHA256 from buildpack.toml
+		// unless the substitute is a different build
+		download := dependency
+		if mapping.URI != "" {
+			download.URI = mapping.URI
+			if mapping.SHA256 != "" {
+				download.SHA256 = mapping.SHA256
+			}
+		} else {
+			download.URI, err = mirrorResolver.FindDependencyMirror(dependency.URI, context.Platform.Path)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
 		logger.Subprocess("Installing Dotnet Core ASPNet %s", dependency.Version)
+		switch {
+		case mapping.URI != "":
+			logger.Action("Substituting %s from the dependency-mapping binding", RedactCredentials(mapping.URI))
+			if mapping.SHA256 != "" {
+				logger.Action("Verifying the substitute against SHA256 %s instead of %s", mapping.SHA256, dependency.SHA256)
+			}
+
+		case download.URI != dependency.URI:
+			logger.Action("Downloading from dependency mirror %s", RedactCredentials(download.URI))
+		}
+
+		// the tarball is kept in the download cache, unless it is a substitute:
+		// the postal.Service would install a substitute from the binding again
+		// rather than from the cache
+		if mapping.URI == "" {
+			cached, err := downloads.Fetch(download, context.CNBPath, downloadsLayer.Path)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if cached.Hit {
+				logger.Action("Download cache hit for SHA256 %s: saved downloading %s", download.SHA256, formatBytes(cached.Size))
+			} else {
+				logger.Action("Download cache miss for SHA256 %s: downloaded %s", download.SHA256, formatBytes(cached.Size))
+			}
+
+			for _, sha := range cached.Evicted {
+				logger.Action("Evicted SHA256 %s from the download cache", sha)
+			}
+
+			download.URI = "file://" + cached.Path
+		}
+
 		duration, err := clock.Measure(func() error {
-			return dependencies.Install(dependency, context.CNBPath, aspNetLayer.Path)
+			return dependencies.Install(download, context.CNBPath, aspNetLayer.Path)
 		})
 		if err != nil {
 			return packit.BuildResult{}, err
@@ -129,12 +476,33 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 		logger.Action("Completed in %s", duration.Round(time.Millisecond))
 		logger.Break()
 
+		err = compatibilityChecker.Check(aspNetLayer.Path, dotnetRoot)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		manifest, err := layerVerifier.Record(aspNetLayer.Path)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
 		aspNetLayer.Metadata = map[string]interface{}{
-			"dependency-sha": dependency.SHA256,
-			"built_at":       clock.Now().Format(time.RFC3339Nano),
+			"dependency-sha":   dependency.SHA256,
+			"built_at":         clock.Now().Format(time.RFC3339Nano),
+			"stack":            context.Stack,
+			"arch":             arch,
+			"layer-format":     LayerFormatVersion,
+			"content-manifest": manifest,
+		}
+
+		if mapping.URI != "" {
+			aspNetLayer.Metadata["dependency-mapping"] = RedactCredentials(mapping.URI)
+			if mapping.SHA256 != "" {
+				aspNetLayer.Metadata["dependency-mapping-sha256"] = mapping.SHA256
+			}
 		}
 
-		aspNetLayer.SharedEnv.Override("DOTNET_ROOT", filepath.Join(context.WorkingDir, ".dotnet_root"))
+		aspNetLayer.SharedEnv.Override("DOTNET_ROOT", dotnetRoot)
 		logger.Environment(aspNetLayer.SharedEnv)
 
 		err = symlinker.Link(context.WorkingDir, aspNetLayer.Path)
@@ -143,7 +511,7 @@ func Build(entries EntryResolver, dependencies DependencyManager, symlinker Syml
 		}
 
 		return packit.BuildResult{
-			Layers: []packit.Layer{aspNetLayer},
+			Layers: []packit.Layer{aspNetLayer, downloadsLayer},
 			Build:  buildMetadata,
 			Launch: launchMetadata,
 		}, nil
@@ -0,0 +1,217 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver"
+	"github.com/paketo-buildpacks/packit/postal"
+)
+
+var pessimisticOperatorRe = regexp.MustCompile(`~>`)
+
+// DependencyConstraint is a release line of a dependency as listed under
+// metadata.dependency-constraints in buildpack.toml. Support is either "lts"
+// or "sts", after the long and standard term support policies of .NET.
+type DependencyConstraint struct {
+	Constraint string `toml:"constraint"`
+	ID         string `toml:"id"`
+	Patches    int    `toml:"patches"`
+	Support    string `toml:"support"`
+}
+
+// buildpackDependency is a dependency as listed in buildpack.toml, where
+// each architecture of a version is a separate dependency.
+type buildpackDependency struct {
+	postal.Dependency
+	Arch string `toml:"arch"`
+}
+
+// availableFor reports whether the dependency can be installed on the given
+// stack and architecture.
+func (d buildpackDependency) availableFor(stack, arch string) bool {
+	return stacksInclude(d.Stacks, stack) && d.architecture() == arch
+}
+
+func (d buildpackDependency) architecture() string {
+	if d.Arch == "" {
+		return DefaultArchitecture
+	}
+	return d.Arch
+}
+
+type buildpackMetadata struct {
+	DefaultVersions       map[string]string      `toml:"default-versions"`
+	Dependencies          []buildpackDependency  `toml:"dependencies"`
+	DependencyConstraints []DependencyConstraint `toml:"dependency-constraints"`
+}
+
+// versionConstraint returns the version constraint that the given version
+// of the dependency with the given id stands for. Like postal.Service, an
+// empty or "default" version selects the default version of the dependency,
+// or else any version, and the pessimistic operator (~>) pins the last
+// component that is given.
+func (m buildpackMetadata) versionConstraint(id, version string) string {
+	if version == "" || version == "default" {
+		version = "*"
+		if defaultVersion, ok := m.DefaultVersions[id]; ok && defaultVersion != "" {
+			version = defaultVersion
+		}
+	}
+
+	if pessimisticOperatorRe.MatchString(version) {
+		res := strings.TrimSpace(pessimisticOperatorRe.ReplaceAllString(version, ""))
+		if len(strings.Split(res, ".")) == 3 {
+			version = "~" + res
+		} else {
+			version = "^" + res
+		}
+	}
+
+	return version
+}
+
+func parseBuildpackMetadata(path string) (buildpackMetadata, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return buildpackMetadata{}, fmt.Errorf("failed to parse buildpack.toml: %w", err)
+	}
+	defer file.Close()
+
+	var buildpack struct {
+		Metadata buildpackMetadata `toml:"metadata"`
+	}
+
+	_, err = toml.DecodeReader(file, &buildpack)
+	if err != nil {
+		return buildpackMetadata{}, fmt.Errorf("failed to parse buildpack.toml: %w", err)
+	}
+
+	return buildpack.Metadata, nil
+}
+
+func parseDependencies(path string) ([]buildpackDependency, error) {
+	metadata, err := parseBuildpackMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.Dependencies, nil
+}
+
+// dependencyCandidate is a dependency that can be installed on the stack and
+// architecture being built for, along with its parsed version.
+type dependencyCandidate struct {
+	buildpackDependency
+	version *semver.Version
+}
+
+// dependencyCandidates are the dependencies with one id that the resolvers
+// choose from: those listed in buildpack.toml for the stack and architecture
+// being built for, in the order they are listed.
+type dependencyCandidates struct {
+	id, stack, arch string
+	candidates      []dependencyCandidate
+
+	// onStack is set if the dependency is available on the stack for any
+	// architecture, and architectures lists those architectures.
+	onStack       bool
+	architectures map[string]bool
+}
+
+// findCandidates returns the dependencies with the given id that can be
+// installed on the given stack and architecture. It returns an
+// invalidVersionError if the version of one of them cannot be parsed.
+func findCandidates(dependencies []buildpackDependency, id, stack, arch string) (dependencyCandidates, error) {
+	c := dependencyCandidates{
+		id:            id,
+		stack:         stack,
+		arch:          arch,
+		architectures: map[string]bool{},
+	}
+
+	for _, dependency := range dependencies {
+		if dependency.ID != id || !stacksInclude(dependency.Stacks, stack) {
+			continue
+		}
+
+		c.onStack = true
+		c.architectures[dependency.architecture()] = true
+
+		if dependency.architecture() != arch {
+			continue
+		}
+
+		v, err := semver.NewVersion(dependency.Version)
+		if err != nil {
+			return dependencyCandidates{}, invalidVersionError{fmt.Errorf("failed to parse %q dependency version %q: %w", id, dependency.Version, err)}
+		}
+
+		c.candidates = append(c.candidates, dependencyCandidate{buildpackDependency: dependency, version: v})
+	}
+
+	return c, nil
+}
+
+// supportedVersions lists the versions of the candidates as they are written
+// in buildpack.toml.
+func (c dependencyCandidates) supportedVersions() []string {
+	var versions []string
+	for _, candidate := range c.candidates {
+		versions = append(versions, candidate.Version)
+	}
+
+	return versions
+}
+
+// noneMatch returns the error for a resolution that none of the candidates
+// satisfy. The failure describes what was requested and why it failed, such
+// as `failed to satisfy "dotnet-aspnetcore" dependency version constraint
+// "5.0.*": no compatible versions`.
+func (c dependencyCandidates) noneMatch(failure string) error {
+	return fmt.Errorf(
+		"%s on %q stack for the %s architecture. Supported versions are: [%s]",
+		failure,
+		c.stack,
+		c.arch,
+		strings.Join(c.supportedVersions(), ", "),
+	)
+}
+
+// releaseOf returns the release of the given version, without its
+// pre-release suffix.
+func releaseOf(v *semver.Version) *semver.Version {
+	if v.Prerelease() == "" {
+		return v
+	}
+
+	return semver.MustParse(fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch()))
+}
+
+// AnyStack is the stack ID of dependencies that are built for every stack.
+const AnyStack = "*"
+
+// stacksInclude reports whether the given stacks include the stack, either
+// by its ID or through the any-stack wildcard.
+func stacksInclude(stacks []string, stack string) bool {
+	for _, s := range stacks {
+		if s == stack || s == AnyStack {
+			return true
+		}
+	}
+	return false
+}
+
+// stacksName reports whether the given stacks name the stack by its ID,
+// rather than only including it through the any-stack wildcard.
+func stacksName(stacks []string, stack string) bool {
+	for _, s := range stacks {
+		if s == stack {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependencyMapping is the location of a substitute for a dependency. If
+// the substitute is a different build of the dependency, SHA256 is its
+// checksum; otherwise it is empty and the substitute is verified against the
+// checksum of the dependency.
+type DependencyMapping struct {
+	URI    string
+	SHA256 string
+}
+
+type DependencyMappingResolver struct {
+	bindingResolver BindingResolver
+}
+
+func NewDependencyMappingResolver(bindingResolver BindingResolver) DependencyMappingResolver {
+	return DependencyMappingResolver{
+		bindingResolver: bindingResolver,
+	}
+}
+
+// FindDependencyMapping returns the substitute for the dependency with the
+// given SHA256 from the dependency-mapping service bindings. A binding maps a
+// dependency by an entry named after its SHA256 whose content is the URI of
+// the substitute, and may give the checksum of the substitute in an entry
+// named <sha256>.sha256. If no binding maps the dependency, an empty
+// DependencyMapping is returned.
+func (d DependencyMappingResolver) FindDependencyMapping(sha256, platformDir string) (DependencyMapping, error) {
+	bindings, err := d.bindingResolver.Resolve("dependency-mapping", "", platformDir)
+	if err != nil {
+		return DependencyMapping{}, fmt.Errorf("failed to resolve 'dependency-mapping' binding: %w", err)
+	}
+
+	for _, binding := range bindings {
+		entry, ok := binding.Entries[sha256]
+		if !ok {
+			continue
+		}
+
+		uri, err := entry.ReadString()
+		if err != nil {
+			return DependencyMapping{}, fmt.Errorf("failed to read 'dependency-mapping' binding entry %q: %w", sha256, err)
+		}
+
+		mapping := DependencyMapping{URI: strings.TrimSpace(uri)}
+
+		if entry, ok := binding.Entries[sha256+".sha256"]; ok {
+			checksum, err := entry.ReadString()
+			if err != nil {
+				return DependencyMapping{}, fmt.Errorf("failed to read 'dependency-mapping' binding entry %q: %w", sha256+".sha256", err)
+			}
+
+			mapping.SHA256 = strings.TrimSpace(checksum)
+		}
+
+		return mapping, nil
+	}
+
+	return DependencyMapping{}, nil
+}
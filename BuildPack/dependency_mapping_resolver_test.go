@@ -0,0 +1,114 @@
+package dotnetcoreaspnet_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/paketo-buildpacks/dotnet-core-aspnet/fakes"
+	"github.com/paketo-buildpacks/packit/servicebindings"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyMappingResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		bindingDir      string
+		bindingResolver *fakes.BindingResolver
+		resolver        dotnetcoreaspnet.DependencyMappingResolver
+	)
+
+	it.Before(func() {
+		var err error
+		bindingDir, err = ioutil.TempDir("", "binding")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ioutil.WriteFile(filepath.Join(bindingDir, "some-sha"), []byte("file:///some/substitute.tar.xz\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bindingDir, "other-sha"), []byte("https://artifacts.example.com/patched.tar.xz"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bindingDir, "other-sha.sha256"), []byte("patched-sha\n"), 0600)).To(Succeed())
+
+		bindingResolver = &fakes.BindingResolver{}
+		bindingResolver.ResolveCall.Returns.BindingSlice = []servicebindings.Binding{
+			{
+				Name:    "empty-binding",
+				Type:    "dependency-mapping",
+				Entries: map[string]*servicebindings.Entry{},
+			},
+			{
+				Name: "some-binding",
+				Path: bindingDir,
+				Type: "dependency-mapping",
+				Entries: map[string]*servicebindings.Entry{
+					"some-sha":         servicebindings.NewEntry(filepath.Join(bindingDir, "some-sha")),
+					"other-sha":        servicebindings.NewEntry(filepath.Join(bindingDir, "other-sha")),
+					"other-sha.sha256": servicebindings.NewEntry(filepath.Join(bindingDir, "other-sha.sha256")),
+				},
+			},
+		}
+
+		resolver = dotnetcoreaspnet.NewDependencyMappingResolver(bindingResolver)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(bindingDir)).To(Succeed())
+	})
+
+	context("FindDependencyMapping", func() {
+		it("returns the substitute for the dependency", func() {
+			mapping, err := resolver.FindDependencyMapping("some-sha", "some-platform")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mapping).To(Equal(dotnetcoreaspnet.DependencyMapping{
+				URI: "file:///some/substitute.tar.xz",
+			}))
+
+			Expect(bindingResolver.ResolveCall.Receives.Typ).To(Equal("dependency-mapping"))
+			Expect(bindingResolver.ResolveCall.Receives.Provider).To(Equal(""))
+			Expect(bindingResolver.ResolveCall.Receives.PlatformDir).To(Equal("some-platform"))
+		})
+
+		it("returns the checksum of a substitute that is a different build", func() {
+			mapping, err := resolver.FindDependencyMapping("other-sha", "some-platform")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mapping).To(Equal(dotnetcoreaspnet.DependencyMapping{
+				URI:    "https://artifacts.example.com/patched.tar.xz",
+				SHA256: "patched-sha",
+			}))
+		})
+
+		it("returns an empty mapping when the dependency is not mapped", func() {
+			mapping, err := resolver.FindDependencyMapping("unmapped-sha", "some-platform")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mapping).To(Equal(dotnetcoreaspnet.DependencyMapping{}))
+		})
+
+		context("failure cases", func() {
+			context("when the bindings cannot be resolved", func() {
+				it.Before(func() {
+					bindingResolver.ResolveCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMapping("some-sha", "some-platform")
+					Expect(err).To(MatchError("failed to resolve 'dependency-mapping' binding: some-error"))
+				})
+			})
+
+			context("when the entry cannot be read", func() {
+				it.Before(func() {
+					Expect(os.Remove(filepath.Join(bindingDir, "some-sha"))).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.FindDependencyMapping("some-sha", "some-platform")
+					Expect(err).To(MatchError(ContainSubstring(`failed to read 'dependency-mapping' binding entry "some-sha"`)))
+				})
+			})
+		})
+	})
+}
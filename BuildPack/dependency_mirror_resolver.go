@@ -0,0 +1,115 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/servicebindings"
+)
+
+var credentialsRe = regexp.MustCompile(`(^|//)[^/@]*@`)
+
+//go:generate faux --interface BindingResolver --output fakes/binding_resolver.go
+type BindingResolver interface {
+	Resolve(typ, provider, platformDir string) ([]servicebindings.Binding, error)
+}
+
+type DependencyMirrorResolver struct {
+	bindingResolver BindingResolver
+}
+
+func NewDependencyMirrorResolver(bindingResolver BindingResolver) DependencyMirrorResolver {
+	return DependencyMirrorResolver{
+		bindingResolver: bindingResolver,
+	}
+}
+
+// FindDependencyMirror returns the URI to download the dependency at the
+// given uri from. Mirrors are configured by a dependency-mirror service
+// binding, or else by environment variables:
+//
+//   - the binding entry named after the hostname of uri, or
+//     $BP_DEPENDENCY_MIRROR_<HOSTNAME>, mirrors that hostname, where
+//     <HOSTNAME> is the hostname in upper case with "." replaced by "_" and
+//     "-" by "__" (e.g. BP_DEPENDENCY_MIRROR_DEPS_PAKETO_IO)
+//   - the binding entry named default, or $BP_DEPENDENCY_MIRROR, mirrors
+//     every other hostname
+//
+// The path of uri is appended to the mirror, and any {originalHost}
+// placeholder in the mirror is replaced by the hostname of uri. If there is
+// no mirror for the hostname, or uri is not an http(s) URI, uri is returned
+// as is.
+func (d DependencyMirrorResolver) FindDependencyMirror(uri, platformDir string) (string, error) {
+	original, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse dependency URI: %w", err)
+	}
+
+	if original.Scheme != "http" && original.Scheme != "https" {
+		return uri, nil
+	}
+
+	bindings, err := d.bindingResolver.Resolve("dependency-mirror", "", platformDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve 'dependency-mirror' binding: %w", err)
+	}
+
+	if len(bindings) > 1 {
+		return "", fmt.Errorf("failed to resolve 'dependency-mirror' binding: found %d bindings but expected at most 1", len(bindings))
+	}
+
+	var mirror string
+	if len(bindings) == 1 {
+		for _, key := range []string{original.Hostname(), "default"} {
+			entry, ok := bindings[0].Entries[key]
+			if !ok {
+				continue
+			}
+
+			mirror, err = entry.ReadString()
+			if err != nil {
+				return "", fmt.Errorf("failed to read 'dependency-mirror' binding entry %q: %w", key, err)
+			}
+
+			break
+		}
+	} else {
+		hostname := strings.NewReplacer(".", "_", "-", "__").Replace(strings.ToUpper(original.Hostname()))
+		for _, name := range []string{"BP_DEPENDENCY_MIRROR_" + hostname, "BP_DEPENDENCY_MIRROR"} {
+			if value, ok := os.LookupEnv(name); ok {
+				mirror = value
+				break
+			}
+		}
+	}
+
+	mirror = strings.TrimSpace(mirror)
+	if mirror == "" {
+		return uri, nil
+	}
+
+	mirror = strings.ReplaceAll(mirror, "{originalHost}", original.Host)
+
+	mirrored, err := url.Parse(strings.TrimSuffix(mirror, "/") + original.EscapedPath())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse dependency mirror %q: %w", RedactCredentials(mirror), err)
+	}
+
+	if mirrored.Scheme == "" || mirrored.Host == "" {
+		return "", fmt.Errorf("failed to parse dependency mirror %q: must be an absolute URI", RedactCredentials(mirror))
+	}
+
+	mirrored.RawQuery = original.RawQuery
+
+	return mirrored.String(), nil
+}
+
+// RedactCredentials replaces the credentials of the given URI, if it has
+// any, so that it can be logged. The URI does not need to parse, so that
+// malformed mirrors are redacted in errors too.
+func RedactCredentials(uri string) string {
+	return credentialsRe.ReplaceAllString(uri, "${1}***@")
+}
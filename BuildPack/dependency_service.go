@@ -0,0 +1,100 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/paketo-buildpacks/packit"
+	"github.com/paketo-buildpacks/packit/postal"
+)
+
+// DependencyService resolves dependencies by architecture as well as by
+// stack, and leaves installing them to a postal.Service.
+type DependencyService struct {
+	service postal.Service
+}
+
+func NewDependencyService(service postal.Service) DependencyService {
+	return DependencyService{
+		service: service,
+	}
+}
+
+// Resolve picks the highest version of the dependency with the given id
+// that satisfies the given version constraint, choosing from the
+// dependencies listed in the buildpack.toml at path for the given stack and
+// architecture. Dependencies built for the any-stack wildcard "*" are
+// candidates on every stack, but a dependency built for the stack itself
+// takes precedence over one of the same version built for any stack. The
+// version is normalized like postal.Service does, see versionConstraint.
+func (s DependencyService) Resolve(path, id, version, stack, arch string) (postal.Dependency, error) {
+	metadata, err := parseBuildpackMetadata(path)
+	if err != nil {
+		return postal.Dependency{}, err
+	}
+
+	version = metadata.versionConstraint(id, version)
+
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return postal.Dependency{}, invalidVersionError{fmt.Errorf("failed to parse version constraint %q: %w", version, err)}
+	}
+
+	found, err := findCandidates(metadata.Dependencies, id, stack, arch)
+	if err != nil {
+		return postal.Dependency{}, err
+	}
+
+	if found.onStack && len(found.candidates) == 0 {
+		return postal.Dependency{}, fmt.Errorf(
+			"no version of %q is available for the %s architecture on %q stack. Supported architectures are: [%s]",
+			id,
+			arch,
+			stack,
+			strings.Join(sortedKeys(found.architectures), ", "),
+		)
+	}
+
+	var compatible []dependencyCandidate
+	for _, candidate := range found.candidates {
+		if constraint.Check(candidate.version) {
+			compatible = append(compatible, candidate)
+		}
+	}
+
+	if len(compatible) == 0 {
+		return postal.Dependency{}, found.noneMatch(fmt.Sprintf("failed to satisfy %q dependency version constraint %q: no compatible versions", id, version))
+	}
+
+	// a dependency built for the stack overrides a dependency of the same
+	// version that is built for any stack
+	sort.SliceStable(compatible, func(i, j int) bool {
+		if !compatible[i].version.Equal(compatible[j].version) {
+			return compatible[i].version.GreaterThan(compatible[j].version)
+		}
+
+		return stacksName(compatible[i].Stacks, stack) && !stacksName(compatible[j].Stacks, stack)
+	})
+
+	return compatible[0].Dependency, nil
+}
+
+func (s DependencyService) Install(dependency postal.Dependency, cnbPath, layerPath string) error {
+	return s.service.Install(dependency, cnbPath, layerPath)
+}
+
+func (s DependencyService) GenerateBillOfMaterials(dependencies ...postal.Dependency) []packit.BOMEntry {
+	return s.service.GenerateBillOfMaterials(dependencies...)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,180 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/postal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyService(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir  string
+		path    string
+		service dotnetcoreaspnet.DependencyService
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = ioutil.TempDir("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(cnbDir, "buildpack.toml")
+		Expect(ioutil.WriteFile(path, []byte(`
+[metadata.default-versions]
+  dotnet-aspnetcore = "5.0.*"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  sha256 = "some-x64-sha-5.0.14"
+  stacks = ["some-stack"]
+  uri = "some-uri/dotnet-aspnetcore_5.0.14_linux_x64.tar.xz"
+  version = "5.0.14"
+
+[[metadata.dependencies]]
+  arch = "amd64"
+  id = "dotnet-aspnetcore"
+  sha256 = "some-x64-sha-6.0.2"
+  stacks = ["some-stack"]
+  uri = "some-uri/dotnet-aspnetcore_6.0.2_linux_x64.tar.xz"
+  version = "6.0.2"
+
+[[metadata.dependencies]]
+  arch = "arm64"
+  id = "dotnet-aspnetcore"
+  sha256 = "some-arm64-sha-6.0.1"
+  stacks = ["some-stack"]
+  uri = "some-uri/dotnet-aspnetcore_6.0.1_linux_arm64.tar.xz"
+  version = "6.0.1"
+
+[[metadata.dependencies]]
+  arch = "arm64"
+  id = "dotnet-aspnetcore"
+  sha256 = "other-arm64-sha-6.0.2"
+  stacks = ["other-stack"]
+  uri = "some-uri/dotnet-aspnetcore_6.0.2_linux_arm64.tar.xz"
+  version = "6.0.2"
+`), 0600)).To(Succeed())
+
+		service = dotnetcoreaspnet.NewDependencyService(postal.NewService(cargo.NewTransport()))
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+	})
+
+	context("Resolve", func() {
+		it("selects the highest version for the architecture", func() {
+			dependency, err := service.Resolve(path, "dotnet-aspnetcore", "6.0.*", "some-stack", "arm64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency).To(Equal(postal.Dependency{
+				ID:      "dotnet-aspnetcore",
+				SHA256:  "some-arm64-sha-6.0.1",
+				Stacks:  []string{"some-stack"},
+				URI:     "some-uri/dotnet-aspnetcore_6.0.1_linux_arm64.tar.xz",
+				Version: "6.0.1",
+			}))
+
+			dependency, err = service.Resolve(path, "dotnet-aspnetcore", "6.0.*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.SHA256).To(Equal("some-x64-sha-6.0.2"))
+		})
+
+		it("treats dependencies without an architecture as amd64", func() {
+			dependency, err := service.Resolve(path, "dotnet-aspnetcore", "5.0.*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.SHA256).To(Equal("some-x64-sha-5.0.14"))
+		})
+
+		it("selects the default version when no version is given", func() {
+			dependency, err := service.Resolve(path, "dotnet-aspnetcore", "", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.Version).To(Equal("5.0.14"))
+		})
+
+		it("supports the pessimistic operator", func() {
+			dependency, err := service.Resolve(path, "dotnet-aspnetcore", "~> 6.0", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.Version).To(Equal("6.0.2"))
+		})
+
+		context("when dependencies are built for any stack", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  sha256 = "any-stack-sha-6.0.2"
+  stacks = ["*"]
+  version = "6.0.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  sha256 = "jammy-sha-6.0.2"
+  stacks = ["io.buildpacks.stacks.jammy"]
+  version = "6.0.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  sha256 = "any-stack-sha-6.0.1"
+  stacks = ["*"]
+  version = "6.0.1"
+`), 0600)).To(Succeed())
+			})
+
+			it("selects them on every stack", func() {
+				dependency, err := service.Resolve(path, "dotnet-aspnetcore", "6.0.*", "some-custom-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.SHA256).To(Equal("any-stack-sha-6.0.2"))
+			})
+
+			it("prefers a dependency built for the stack", func() {
+				dependency, err := service.Resolve(path, "dotnet-aspnetcore", "6.0.*", "io.buildpacks.stacks.jammy", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.SHA256).To(Equal("jammy-sha-6.0.2"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when buildpack.toml cannot be parsed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := service.Resolve(path, "dotnet-aspnetcore", "6.0.*", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+
+			context("when the version constraint cannot be parsed", func() {
+				it("returns an error", func() {
+					_, err := service.Resolve(path, "dotnet-aspnetcore", "not-a-constraint", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse version constraint "not-a-constraint"`)))
+				})
+			})
+
+			context("when no version for the architecture satisfies the constraint", func() {
+				it("returns an error", func() {
+					_, err := service.Resolve(path, "dotnet-aspnetcore", "5.0.*", "some-stack", "arm64")
+					Expect(err).To(MatchError(`failed to satisfy "dotnet-aspnetcore" dependency version constraint "5.0.*": no compatible versions on "some-stack" stack for the arm64 architecture. Supported versions are: [6.0.1]`))
+				})
+			})
+
+			context("when there is no version for the architecture", func() {
+				it("returns an error naming the architecture", func() {
+					_, err := service.Resolve(path, "dotnet-aspnetcore", "6.0.*", "other-stack", "amd64")
+					Expect(err).To(MatchError(`no version of "dotnet-aspnetcore" is available for the amd64 architecture on "other-stack" stack. Supported architectures are: [arm64]`))
+				})
+			})
+		})
+	})
+}
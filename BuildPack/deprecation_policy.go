@@ -0,0 +1,52 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DeprecationPolicyIgnore = "ignore"
+	DeprecationPolicyWarn   = "warn"
+	DeprecationPolicyFail   = "fail"
+)
+
+// DeprecationPolicy configures how Build treats a dependency that is past, or
+// within Window of, its deprecation_date.
+type DeprecationPolicy struct {
+	Action string
+	Window time.Duration
+}
+
+// LoadDeprecationPolicy reads the policy from
+// $BP_DOTNET_ASPNET_DEPRECATION_POLICY and the warning window from
+// $BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS, defaulting to warning 30 days
+// ahead of the deprecation date.
+func LoadDeprecationPolicy() (DeprecationPolicy, error) {
+	policy := DeprecationPolicy{
+		Action: DeprecationPolicyWarn,
+		Window: 30 * 24 * time.Hour,
+	}
+
+	if action, ok := os.LookupEnv("BP_DOTNET_ASPNET_DEPRECATION_POLICY"); ok {
+		policy.Action = strings.ToLower(action)
+		switch policy.Action {
+		case DeprecationPolicyIgnore, DeprecationPolicyWarn, DeprecationPolicyFail:
+		default:
+			return DeprecationPolicy{}, fmt.Errorf("invalid $BP_DOTNET_ASPNET_DEPRECATION_POLICY %q: must be one of ignore, warn or fail", action)
+		}
+	}
+
+	if days, ok := os.LookupEnv("BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return DeprecationPolicy{}, fmt.Errorf("invalid $BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS %q: must be a non-negative number of days", days)
+		}
+		policy.Window = time.Duration(n) * 24 * time.Hour
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,80 @@
+package dotnetcoreaspnet_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDeprecationPolicy(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("LoadDeprecationPolicy", func() {
+		it("defaults to warning 30 days ahead", func() {
+			policy, err := dotnetcoreaspnet.LoadDeprecationPolicy()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(Equal(dotnetcoreaspnet.DeprecationPolicy{
+				Action: dotnetcoreaspnet.DeprecationPolicyWarn,
+				Window: 30 * 24 * time.Hour,
+			}))
+		})
+
+		context("when the policy and window are set", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_ASPNET_DEPRECATION_POLICY", "FAIL")).To(Succeed())
+				Expect(os.Setenv("BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS", "90")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_DEPRECATION_POLICY")).To(Succeed())
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS")).To(Succeed())
+			})
+
+			it("uses them", func() {
+				policy, err := dotnetcoreaspnet.LoadDeprecationPolicy()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy).To(Equal(dotnetcoreaspnet.DeprecationPolicy{
+					Action: dotnetcoreaspnet.DeprecationPolicyFail,
+					Window: 90 * 24 * time.Hour,
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the policy is invalid", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_ASPNET_DEPRECATION_POLICY", "explode")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_ASPNET_DEPRECATION_POLICY")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := dotnetcoreaspnet.LoadDeprecationPolicy()
+					Expect(err).To(MatchError(`invalid $BP_DOTNET_ASPNET_DEPRECATION_POLICY "explode": must be one of ignore, warn or fail`))
+				})
+			})
+
+			context("when the window is invalid", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS", "-1")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := dotnetcoreaspnet.LoadDeprecationPolicy()
+					Expect(err).To(MatchError(`invalid $BP_DOTNET_ASPNET_DEPRECATION_WARNING_DAYS "-1": must be a non-negative number of days`))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package dotnetcoreaspnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type DepsJSON struct {
+	Path             string
+	ReferencesAspNet bool
+	SelfContained    bool
+}
+
+type DepsJSONParser struct{}
+
+func NewDepsJSONParser() DepsJSONParser {
+	return DepsJSONParser{}
+}
+
+// Parse finds the *.deps.json file matching the given glob and reports
+// whether any of the libraries it lists belong to ASP.NET Core, and whether
+// the app was published as self-contained, in which case its targets include
+// a runtime pack. If there is no deps.json file, an empty DepsJSON is
+// returned.
+func (p DepsJSONParser) Parse(glob string) (DepsJSON, error) {
+	files, err := filepath.Glob(glob)
+	if err != nil {
+		return DepsJSON{}, err
+	}
+
+	if len(files) == 0 {
+		return DepsJSON{}, nil
+	}
+
+	if len(files) > 1 {
+		return DepsJSON{}, fmt.Errorf("multiple *.deps.json files present: %s", strings.Join(files, ", "))
+	}
+
+	deps := DepsJSON{Path: files[0]}
+
+	file, err := os.Open(deps.Path)
+	if err != nil {
+		return DepsJSON{}, err
+	}
+	defer file.Close()
+
+	var data struct {
+		Targets   map[string]map[string]json.RawMessage `json:"targets"`
+		Libraries map[string]json.RawMessage            `json:"libraries"`
+	}
+
+	err = json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		return DepsJSON{}, fmt.Errorf("failed to decode %s: %w", filepath.Base(deps.Path), err)
+	}
+
+	// libraries are keyed by name/version, e.g. Microsoft.AspNetCore.Mvc.NewtonsoftJson/6.0.2
+	// or runtimepack.Microsoft.AspNetCore.App.Runtime.linux-x64/6.0.2
+	for library := range data.Libraries {
+		name := strings.TrimPrefix(library, "runtimepack.")
+		if strings.HasPrefix(name, "Microsoft.AspNetCore.") {
+			deps.ReferencesAspNet = true
+			break
+		}
+	}
+
+	for _, target := range data.Targets {
+		for library := range target {
+			if strings.HasPrefix(library, "runtimepack.") {
+				deps.SelfContained = true
+			}
+		}
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,148 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDepsJSONParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		glob       string
+		parser     dotnetcoreaspnet.DepsJSONParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = ioutil.TempDir("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		glob = filepath.Join(workingDir, "*.deps.json")
+
+		parser = dotnetcoreaspnet.NewDepsJSONParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Parse", func() {
+		context("when the deps.json lists an ASP.NET Core library", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.deps.json"), []byte(`{
+  "libraries": {
+    "some-app/1.0.0": {
+      "type": "project"
+    },
+    "Microsoft.AspNetCore.Authentication.JwtBearer/6.0.2": {
+      "type": "package"
+    }
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("reports that the app references ASP.NET Core", func() {
+				deps, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deps).To(Equal(dotnetcoreaspnet.DepsJSON{
+					Path:             filepath.Join(workingDir, "some-app.deps.json"),
+					ReferencesAspNet: true,
+				}))
+			})
+		})
+
+		context("when the deps.json lists the ASP.NET Core runtime pack", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.deps.json"), []byte(`{
+  "runtimeTarget": {
+    "name": ".NETCoreApp,Version=v6.0/linux-x64"
+  },
+  "targets": {
+    ".NETCoreApp,Version=v6.0": {},
+    ".NETCoreApp,Version=v6.0/linux-x64": {
+      "some-app/1.0.0": {},
+      "runtimepack.Microsoft.AspNetCore.App.Runtime.linux-x64/6.0.2": {}
+    }
+  },
+  "libraries": {
+    "runtimepack.Microsoft.AspNetCore.App.Runtime.linux-x64/6.0.2": {
+      "type": "runtimepack"
+    }
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("reports that the app is self-contained and references ASP.NET Core", func() {
+				deps, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deps).To(Equal(dotnetcoreaspnet.DepsJSON{
+					Path:             filepath.Join(workingDir, "some-app.deps.json"),
+					ReferencesAspNet: true,
+					SelfContained:    true,
+				}))
+			})
+		})
+
+		context("when the deps.json does not list an ASP.NET Core library", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.deps.json"), []byte(`{
+  "libraries": {
+    "Newtonsoft.Json/13.0.1": {
+      "type": "package"
+    }
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("reports that the app does not reference ASP.NET Core", func() {
+				deps, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deps).To(Equal(dotnetcoreaspnet.DepsJSON{
+					Path: filepath.Join(workingDir, "some-app.deps.json"),
+				}))
+			})
+		})
+
+		context("when there is no deps.json", func() {
+			it("returns an empty DepsJSON", func() {
+				deps, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deps).To(Equal(dotnetcoreaspnet.DepsJSON{}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when there are multiple deps.json files", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.deps.json"), []byte(`{}`), 0600)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "other-app.deps.json"), []byte(`{}`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.Parse(glob)
+					Expect(err).To(MatchError(ContainSubstring("multiple *.deps.json files present")))
+				})
+			})
+
+			context("when the deps.json is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.deps.json"), []byte(`{`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.Parse(glob)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode some-app.deps.json")))
+				})
+			})
+		})
+	})
+}
@@ -1,9 +1,13 @@
 package dotnetcoreaspnet
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/Masterminds/semver"
 	"github.com/paketo-buildpacks/packit"
 )
 
@@ -12,8 +16,40 @@ type VersionParser interface {
 	ParseVersion(path string) (version string, err error)
 }
 
-func Detect(buildpackYMLParser VersionParser) packit.DetectFunc {
+//go:generate faux --interface ProjectParser --output fakes/project_parser.go
+type ProjectParser interface {
+	FindProjectFile(dir, root string) (string, error)
+	IsWebProject(path string) (bool, error)
+	ParseTargetFrameworks(path, root string) ([]string, error)
+}
+
+//go:generate faux --interface ConfigParser --output fakes/config_parser.go
+type ConfigParser interface {
+	Parse(glob string) (RuntimeConfig, error)
+}
+
+//go:generate faux --interface GlobalConfigParser --output fakes/global_config_parser.go
+type GlobalConfigParser interface {
+	Parse(path string) (GlobalJSON, error)
+}
+
+//go:generate faux --interface DepsParser --output fakes/deps_parser.go
+type DepsParser interface {
+	Parse(glob string) (DepsJSON, error)
+}
+
+func Detect(buildpackYMLParser VersionParser, globalJSONParser GlobalConfigParser, projectParser ProjectParser, runtimeConfigParser ConfigParser, depsParser DepsParser) packit.DetectFunc {
 	return func(context packit.DetectContext) (packit.DetectResult, error) {
+		// in strict mode, detection only passes for apps that use ASP.NET Core
+		var strict bool
+		if value, ok := os.LookupEnv("BP_DOTNET_ASPNET_STRICT_DETECTION"); ok {
+			var err error
+			strict, err = strconv.ParseBool(value)
+			if err != nil {
+				return packit.DetectResult{}, fmt.Errorf("failed to parse $BP_DOTNET_ASPNET_STRICT_DETECTION: %w", err)
+			}
+		}
+
 		var requirements = []packit.BuildPlanRequirement{
 			{
 				Name: "dotnet-runtime",
@@ -23,6 +59,30 @@ func Detect(buildpackYMLParser VersionParser) packit.DetectFunc {
 			},
 		}
 
+		// file-based version sources are read from the project directory, which
+		// defaults to the application root
+		projectPath := context.WorkingDir
+		if path, ok := os.LookupEnv("BP_DOTNET_PROJECT_PATH"); ok {
+			projectPath = filepath.Join(context.WorkingDir, path)
+
+			rel, err := filepath.Rel(context.WorkingDir, projectPath)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return packit.DetectResult{}, fmt.Errorf("$BP_DOTNET_PROJECT_PATH %q must be a path within the application directory", path)
+			}
+
+			info, err := os.Stat(projectPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return packit.DetectResult{}, fmt.Errorf("$BP_DOTNET_PROJECT_PATH %q does not exist in the application directory", path)
+				}
+				return packit.DetectResult{}, err
+			}
+
+			if !info.IsDir() {
+				return packit.DetectResult{}, fmt.Errorf("$BP_DOTNET_PROJECT_PATH %q is not a directory", path)
+			}
+		}
+
 		// check if BP_DOTNET_FRAMEWORK_VERSION is set
 		if version, ok := os.LookupEnv("BP_DOTNET_FRAMEWORK_VERSION"); ok {
 			requirements = append(requirements, packit.BuildPlanRequirement{
@@ -35,7 +95,7 @@ func Detect(buildpackYMLParser VersionParser) packit.DetectFunc {
 		}
 
 		// check if the version is set in the buildpack.yml
-		version, err := buildpackYMLParser.ParseVersion(filepath.Join(context.WorkingDir, "buildpack.yml"))
+		version, err := buildpackYMLParser.ParseVersion(filepath.Join(projectPath, "buildpack.yml"))
 		if err != nil {
 			return packit.DetectResult{}, err
 		}
@@ -50,6 +110,124 @@ func Detect(buildpackYMLParser VersionParser) packit.DetectFunc {
 			})
 		}
 
+		// check if the SDK is pinned by a global.json, in which case the app
+		// targets the runtime that ships with that SDK. Like the SDK, look for
+		// it from the project directory up to the application root.
+		var globalJSON GlobalJSON
+		for dir := projectPath; ; dir = filepath.Dir(dir) {
+			globalJSON, err = globalJSONParser.Parse(filepath.Join(dir, "global.json"))
+			if err != nil {
+				return packit.DetectResult{}, err
+			}
+
+			if globalJSON.SDKVersion != "" || dir == filepath.Clean(context.WorkingDir) || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+
+		if globalJSON.SDKVersion != "" {
+			sdkVersion, err := semver.NewVersion(globalJSON.SDKVersion)
+			if err != nil {
+				return packit.DetectResult{}, fmt.Errorf("failed to parse SDK version from global.json: %w", err)
+			}
+
+			requirements = append(requirements, packit.BuildPlanRequirement{
+				Name: "dotnet-aspnetcore",
+				Metadata: map[string]interface{}{
+					"version-source": "global.json",
+					"version":        fmt.Sprintf("%d.%d.0", sdkVersion.Major(), sdkVersion.Minor()),
+					"roll-forward":   RuntimeRollForward(globalJSON.RollForward),
+				},
+			})
+		}
+
+		// check if the version is set by the TargetFramework of a project file
+		projectFile, err := projectParser.FindProjectFile(projectPath, context.WorkingDir)
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		var webProject bool
+		if projectFile != "" {
+			if strict {
+				webProject, err = projectParser.IsWebProject(projectFile)
+				if err != nil {
+					return packit.DetectResult{}, err
+				}
+			}
+
+			frameworks, err := projectParser.ParseTargetFrameworks(projectFile, context.WorkingDir)
+			if err != nil {
+				return packit.DetectResult{}, err
+			}
+
+			framework, err := selectTargetFramework(frameworks, os.Getenv("BP_DOTNET_TARGET_FRAMEWORK"))
+			if err != nil {
+				return packit.DetectResult{}, err
+			}
+
+			if framework != "" {
+				metadata := map[string]interface{}{
+					"version-source": filepath.Base(projectFile),
+					"version":        TargetFrameworkVersion(framework),
+				}
+
+				// record how a multi-targeting project was narrowed down so that
+				// the decision can be logged during the build
+				if len(frameworks) > 1 {
+					metadata["target-frameworks"] = strings.Join(frameworks, ";")
+					metadata["target-framework"] = framework
+				}
+
+				requirements = append(requirements, packit.BuildPlanRequirement{
+					Name:     "dotnet-aspnetcore",
+					Metadata: metadata,
+				})
+			}
+		}
+
+		// check if the app was published with a reference to the ASP.NET framework
+		config, err := runtimeConfigParser.Parse(filepath.Join(projectPath, "*.runtimeconfig.json"))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if config.Version != "" {
+			metadata := map[string]interface{}{
+				"version-source": "runtimeconfig.json",
+				"version":        config.Version,
+			}
+
+			if config.RollForward != "" {
+				metadata["roll-forward"] = config.RollForward
+			}
+
+			requirements = append(requirements, packit.BuildPlanRequirement{
+				Name:     "dotnet-aspnetcore",
+				Metadata: metadata,
+			})
+		}
+
+		// check if the app was published as self-contained, in which case it
+		// ships its own ASP.NET runtime and the layer does not need installing
+		deps, err := depsParser.Parse(filepath.Join(projectPath, "*.deps.json"))
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		if config.SelfContained || deps.SelfContained {
+			requirements = append(requirements, packit.BuildPlanRequirement{
+				Name: "dotnet-aspnetcore",
+				Metadata: map[string]interface{}{
+					"self-contained": true,
+				},
+			})
+		}
+
+		if strict && !webProject && config.Version == "" && !deps.ReferencesAspNet {
+			return packit.DetectResult{}, packit.Fail.WithMessage("$BP_DOTNET_ASPNET_STRICT_DETECTION is set and the app shows no use of ASP.NET Core: expected a Microsoft.NET.Sdk.Web project, a Microsoft.AspNetCore.App reference in *.runtimeconfig.json or an ASP.NET Core library in *.deps.json")
+		}
+
 		return packit.DetectResult{
 			Plan: packit.BuildPlan{
 				Provides: []packit.BuildPlanProvision{
@@ -2,7 +2,9 @@ package dotnetcoreaspnet_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
@@ -17,15 +19,23 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 	var (
 		Expect = NewWithT(t).Expect
 
-		workingDir         string
-		buildpackYMLParser *fakes.VersionParser
-		detect             packit.DetectFunc
+		workingDir          string
+		buildpackYMLParser  *fakes.VersionParser
+		globalJSONParser    *fakes.GlobalConfigParser
+		projectParser       *fakes.ProjectParser
+		runtimeConfigParser *fakes.ConfigParser
+		depsParser          *fakes.DepsParser
+		detect              packit.DetectFunc
 	)
 
 	it.Before(func() {
 		workingDir = "some-working-dir"
 		buildpackYMLParser = &fakes.VersionParser{}
-		detect = dotnetcoreaspnet.Detect(buildpackYMLParser)
+		globalJSONParser = &fakes.GlobalConfigParser{}
+		projectParser = &fakes.ProjectParser{}
+		runtimeConfigParser = &fakes.ConfigParser{}
+		depsParser = &fakes.DepsParser{}
+		detect = dotnetcoreaspnet.Detect(buildpackYMLParser, globalJSONParser, projectParser, runtimeConfigParser, depsParser)
 	})
 
 	it.After(func() {
@@ -128,6 +138,400 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("when src code contains a global.json", func() {
+		it.Before(func() {
+			globalJSONParser.ParseCall.Returns.GlobalJSON = dotnetcoreaspnet.GlobalJSON{
+				SDKVersion:  "6.0.100",
+				RollForward: "latestMinor",
+			}
+		})
+
+		it("provides dotnet-aspnetcore and requires the runtime that ships with the SDK", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{
+						Name: "dotnet-aspnetcore",
+					},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dotnet-runtime",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+					{
+						Name: "dotnet-aspnetcore",
+						Metadata: map[string]interface{}{
+							"version-source": "global.json",
+							"version":        "6.0.0",
+							"roll-forward":   "LatestMinor",
+						},
+					},
+				},
+			}))
+
+			Expect(globalJSONParser.ParseCall.Receives.Path).To(Equal("some-working-dir/global.json"))
+		})
+	})
+
+	context("when src code contains a project file", func() {
+		it.Before(func() {
+			projectParser.FindProjectFileCall.Returns.String = "some-working-dir/some-app.csproj"
+			projectParser.ParseTargetFrameworksCall.Returns.StringSlice = []string{"net6.0"}
+		})
+
+		it("provides dotnet-aspnetcore and requires the version targeted by the project file", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{
+						Name: "dotnet-aspnetcore",
+					},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dotnet-runtime",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+					{
+						Name: "dotnet-aspnetcore",
+						Metadata: map[string]interface{}{
+							"version-source": "some-app.csproj",
+							"version":        "6.0.*",
+						},
+					},
+				},
+			}))
+
+			Expect(projectParser.FindProjectFileCall.Receives.Dir).To(Equal(workingDir))
+			Expect(projectParser.FindProjectFileCall.Receives.Root).To(Equal(workingDir))
+			Expect(projectParser.ParseTargetFrameworksCall.Receives.Path).To(Equal("some-working-dir/some-app.csproj"))
+			Expect(projectParser.ParseTargetFrameworksCall.Receives.Root).To(Equal(workingDir))
+		})
+
+		context("when the project file targets multiple frameworks", func() {
+			it.Before(func() {
+				projectParser.ParseTargetFrameworksCall.Returns.StringSlice = []string{"net6.0", "netstandard2.1", "net7.0", "netcoreapp3.1"}
+			})
+
+			it("requires the version of the highest target framework", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "dotnet-aspnetcore",
+					Metadata: map[string]interface{}{
+						"version-source":    "some-app.csproj",
+						"version":           "7.0.*",
+						"target-frameworks": "net6.0;netstandard2.1;net7.0;netcoreapp3.1",
+						"target-framework":  "net7.0",
+					},
+				}))
+			})
+
+			context("when BP_DOTNET_TARGET_FRAMEWORK is set", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_TARGET_FRAMEWORK", "NET6.0")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_TARGET_FRAMEWORK")).To(Succeed())
+				})
+
+				it("requires the version of the selected target framework", func() {
+					result, err := detect(packit.DetectContext{
+						WorkingDir: workingDir,
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+						Name: "dotnet-aspnetcore",
+						Metadata: map[string]interface{}{
+							"version-source":    "some-app.csproj",
+							"version":           "6.0.*",
+							"target-frameworks": "net6.0;netstandard2.1;net7.0;netcoreapp3.1",
+							"target-framework":  "net6.0",
+						},
+					}))
+				})
+			})
+		})
+
+		context("when the project file does not declare a supported TargetFramework", func() {
+			it.Before(func() {
+				projectParser.ParseTargetFrameworksCall.Returns.StringSlice = []string{"netstandard2.0"}
+			})
+
+			it("does not require a specific version of dotnet-aspnetcore", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: "dotnet-runtime",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+				}))
+			})
+		})
+	})
+
+	context("when src code contains a runtimeconfig.json", func() {
+		it.Before(func() {
+			runtimeConfigParser.ParseCall.Returns.RuntimeConfig = dotnetcoreaspnet.RuntimeConfig{
+				Path:    "some-working-dir/some-app.runtimeconfig.json",
+				Version: "6.0.0",
+			}
+		})
+
+		it("provides dotnet-aspnetcore and requires the referenced framework version", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Provides: []packit.BuildPlanProvision{
+					{
+						Name: "dotnet-aspnetcore",
+					},
+				},
+				Requires: []packit.BuildPlanRequirement{
+					{
+						Name: "dotnet-runtime",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+					{
+						Name: "dotnet-aspnetcore",
+						Metadata: map[string]interface{}{
+							"version-source": "runtimeconfig.json",
+							"version":        "6.0.0",
+						},
+					},
+				},
+			}))
+
+			Expect(runtimeConfigParser.ParseCall.Receives.Glob).To(Equal("some-working-dir/*.runtimeconfig.json"))
+		})
+
+		context("when the runtimeconfig.json sets a roll-forward policy", func() {
+			it.Before(func() {
+				runtimeConfigParser.ParseCall.Returns.RuntimeConfig.RollForward = "LatestMinor"
+			})
+
+			it("requires the referenced framework version with the roll-forward policy", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "dotnet-aspnetcore",
+					Metadata: map[string]interface{}{
+						"version-source": "runtimeconfig.json",
+						"version":        "6.0.0",
+						"roll-forward":   "LatestMinor",
+					},
+				}))
+			})
+		})
+	})
+
+	context("when the app is self-contained", func() {
+		context("when the deps.json includes a runtime pack", func() {
+			it.Before(func() {
+				depsParser.ParseCall.Returns.DepsJSON = dotnetcoreaspnet.DepsJSON{
+					Path:          "/working-dir/some-app.deps.json",
+					SelfContained: true,
+				}
+			})
+
+			it("requires dotnet-aspnetcore as self-contained", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "dotnet-aspnetcore",
+					Metadata: map[string]interface{}{
+						"self-contained": true,
+					},
+				}))
+
+				Expect(depsParser.ParseCall.Receives.Glob).To(Equal("/working-dir/*.deps.json"))
+			})
+		})
+
+		context("when the runtimeconfig.json lists included frameworks", func() {
+			it.Before(func() {
+				runtimeConfigParser.ParseCall.Returns.RuntimeConfig = dotnetcoreaspnet.RuntimeConfig{
+					Path:          "/working-dir/some-app.runtimeconfig.json",
+					SelfContained: true,
+				}
+			})
+
+			it("requires dotnet-aspnetcore as self-contained", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: "dotnet-runtime",
+						Metadata: map[string]interface{}{
+							"build": true,
+						},
+					},
+					{
+						Name: "dotnet-aspnetcore",
+						Metadata: map[string]interface{}{
+							"self-contained": true,
+						},
+					},
+				}))
+			})
+		})
+	})
+
+	context("when BP_DOTNET_PROJECT_PATH is set", func() {
+		it.Before(func() {
+			var err error
+			workingDir, err = ioutil.TempDir("", "working-dir")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(workingDir, "src", "some-app"), os.ModePerm)).To(Succeed())
+			Expect(os.Setenv("BP_DOTNET_PROJECT_PATH", "src/some-app")).To(Succeed())
+
+			globalJSONParser.ParseCall.Stub = func(path string) (dotnetcoreaspnet.GlobalJSON, error) {
+				if path == filepath.Join(workingDir, "global.json") {
+					return dotnetcoreaspnet.GlobalJSON{SDKVersion: "6.0.100"}, nil
+				}
+				return dotnetcoreaspnet.GlobalJSON{}, nil
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_DOTNET_PROJECT_PATH")).To(Succeed())
+		})
+
+		it("reads the file-based version sources relative to the project path", func() {
+			result, err := detect(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+				Name: "dotnet-aspnetcore",
+				Metadata: map[string]interface{}{
+					"version-source": "global.json",
+					"version":        "6.0.0",
+					"roll-forward":   "LatestPatch",
+				},
+			}))
+
+			Expect(buildpackYMLParser.ParseVersionCall.Receives.Path).To(Equal(filepath.Join(workingDir, "src", "some-app", "buildpack.yml")))
+			Expect(globalJSONParser.ParseCall.CallCount).To(Equal(3))
+			Expect(projectParser.FindProjectFileCall.Receives.Dir).To(Equal(filepath.Join(workingDir, "src", "some-app")))
+			Expect(projectParser.FindProjectFileCall.Receives.Root).To(Equal(workingDir))
+			Expect(runtimeConfigParser.ParseCall.Receives.Glob).To(Equal(filepath.Join(workingDir, "src", "some-app", "*.runtimeconfig.json")))
+		})
+	})
+
+	context("when BP_DOTNET_ASPNET_STRICT_DETECTION is set", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_DOTNET_ASPNET_STRICT_DETECTION", "true")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_DOTNET_ASPNET_STRICT_DETECTION")).To(Succeed())
+		})
+
+		context("when the project file uses the web SDK", func() {
+			it.Before(func() {
+				projectParser.FindProjectFileCall.Returns.String = "/working-dir/some-app.csproj"
+				projectParser.IsWebProjectCall.Returns.Bool = true
+			})
+
+			it("detects", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Provides).To(Equal([]packit.BuildPlanProvision{
+					{Name: "dotnet-aspnetcore"},
+				}))
+
+				Expect(projectParser.IsWebProjectCall.Receives.Path).To(Equal("/working-dir/some-app.csproj"))
+			})
+		})
+
+		context("when the runtimeconfig.json references the ASP.NET framework", func() {
+			it.Before(func() {
+				runtimeConfigParser.ParseCall.Returns.RuntimeConfig = dotnetcoreaspnet.RuntimeConfig{
+					Path:    "/working-dir/some-app.runtimeconfig.json",
+					Version: "6.0.0",
+				}
+			})
+
+			it("detects", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Provides).To(Equal([]packit.BuildPlanProvision{
+					{Name: "dotnet-aspnetcore"},
+				}))
+			})
+		})
+
+		context("when the deps.json lists an ASP.NET Core library", func() {
+			it.Before(func() {
+				depsParser.ParseCall.Returns.DepsJSON = dotnetcoreaspnet.DepsJSON{
+					Path:             "/working-dir/some-app.deps.json",
+					ReferencesAspNet: true,
+				}
+			})
+
+			it("detects", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Provides).To(Equal([]packit.BuildPlanProvision{
+					{Name: "dotnet-aspnetcore"},
+				}))
+
+				Expect(depsParser.ParseCall.Receives.Glob).To(Equal("/working-dir/*.deps.json"))
+			})
+		})
+
+		context("when the app shows no use of ASP.NET Core", func() {
+			it.Before(func() {
+				projectParser.FindProjectFileCall.Returns.String = "/working-dir/some-app.csproj"
+				projectParser.ParseTargetFrameworksCall.Returns.StringSlice = []string{"net6.0"}
+			})
+
+			it("fails detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError(packit.Fail.WithMessage("$BP_DOTNET_ASPNET_STRICT_DETECTION is set and the app shows no use of ASP.NET Core: expected a Microsoft.NET.Sdk.Web project, a Microsoft.AspNetCore.App reference in *.runtimeconfig.json or an ASP.NET Core library in *.deps.json")))
+			})
+		})
+	})
+
 	context("failure cases", func() {
 		context("when the buildpack.yml parser fails", func() {
 			it.Before(func() {
@@ -141,5 +545,203 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 				Expect(err).To(MatchError("failed to parse buildpack.yml"))
 			})
 		})
+
+		context("when BP_DOTNET_PROJECT_PATH does not exist", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_PROJECT_PATH", "src/some-app")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_PROJECT_PATH")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError(`$BP_DOTNET_PROJECT_PATH "src/some-app" does not exist in the application directory`))
+			})
+		})
+
+		context("when BP_DOTNET_PROJECT_PATH is outside of the application directory", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_PROJECT_PATH", "../some-app")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_PROJECT_PATH")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError(`$BP_DOTNET_PROJECT_PATH "../some-app" must be a path within the application directory`))
+			})
+		})
+
+		context("when BP_DOTNET_PROJECT_PATH is a file", func() {
+			it.Before(func() {
+				var err error
+				workingDir, err = ioutil.TempDir("", "working-dir")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-file"), nil, 0600)).To(Succeed())
+				Expect(os.Setenv("BP_DOTNET_PROJECT_PATH", "some-file")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_PROJECT_PATH")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(`$BP_DOTNET_PROJECT_PATH "some-file" is not a directory`))
+			})
+		})
+
+		context("when the global.json parser fails", func() {
+			it.Before(func() {
+				globalJSONParser.ParseCall.Returns.Error = errors.New("failed to parse global.json")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError("failed to parse global.json"))
+			})
+		})
+
+		context("when the global.json SDK version is invalid", func() {
+			it.Before(func() {
+				globalJSONParser.ParseCall.Returns.GlobalJSON = dotnetcoreaspnet.GlobalJSON{
+					SDKVersion: "not-a-version",
+				}
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse SDK version from global.json")))
+			})
+		})
+
+		context("when the project file cannot be found", func() {
+			it.Before(func() {
+				projectParser.FindProjectFileCall.Returns.Error = errors.New("failed to find project file")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError("failed to find project file"))
+			})
+		})
+
+		context("when the project file parser fails", func() {
+			it.Before(func() {
+				projectParser.FindProjectFileCall.Returns.String = "/working-dir/some-app.csproj"
+				projectParser.ParseTargetFrameworksCall.Returns.Error = errors.New("failed to parse project file")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError("failed to parse project file"))
+			})
+		})
+
+		context("when BP_DOTNET_TARGET_FRAMEWORK is not a target framework of the project", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_TARGET_FRAMEWORK", "net5.0")).To(Succeed())
+
+				projectParser.FindProjectFileCall.Returns.String = "/working-dir/some-app.csproj"
+				projectParser.ParseTargetFrameworksCall.Returns.StringSlice = []string{"net6.0", "net7.0"}
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_TARGET_FRAMEWORK")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError(`$BP_DOTNET_TARGET_FRAMEWORK "net5.0" does not match a supported target framework of the project: [net6.0, net7.0]`))
+			})
+		})
+
+		context("when the runtimeconfig.json parser fails", func() {
+			it.Before(func() {
+				runtimeConfigParser.ParseCall.Returns.Error = errors.New("failed to parse runtimeconfig.json")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError("failed to parse runtimeconfig.json"))
+			})
+		})
+
+		context("when BP_DOTNET_ASPNET_STRICT_DETECTION is not a boolean", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_ASPNET_STRICT_DETECTION", "sometimes")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_STRICT_DETECTION")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse $BP_DOTNET_ASPNET_STRICT_DETECTION")))
+			})
+		})
+
+		context("when the project file cannot be checked for the web SDK", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_ASPNET_STRICT_DETECTION", "true")).To(Succeed())
+				projectParser.FindProjectFileCall.Returns.String = "/working-dir/some-app.csproj"
+				projectParser.IsWebProjectCall.Returns.Error = errors.New("failed to decode some-app.csproj")
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_STRICT_DETECTION")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError("failed to decode some-app.csproj"))
+			})
+		})
+
+		context("when the deps.json parser fails", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_ASPNET_STRICT_DETECTION", "true")).To(Succeed())
+				depsParser.ParseCall.Returns.Error = errors.New("failed to parse deps.json")
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_STRICT_DETECTION")).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: "/working-dir",
+				})
+				Expect(err).To(MatchError("failed to parse deps.json"))
+			})
+		})
 	})
 }
@@ -0,0 +1,247 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/chronos"
+	"github.com/paketo-buildpacks/packit/postal"
+)
+
+// DefaultDownloadCacheSize is the number of dependency tarballs kept in the
+// download cache unless $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE says otherwise.
+const DefaultDownloadCacheSize = 3
+
+const downloadCacheIndex = "index.toml"
+
+//go:generate faux --interface DownloadTransport --output fakes/download_transport.go
+type DownloadTransport interface {
+	Drop(root, uri string) (io.ReadCloser, error)
+}
+
+// CachedDownload is a dependency tarball in the download cache. Hit is true
+// if the tarball was already in the cache, and Evicted lists the SHA256s of
+// the tarballs that were evicted to make room for it.
+type CachedDownload struct {
+	Path    string
+	Size    int64
+	Hit     bool
+	Evicted []string
+}
+
+type cachedTarball struct {
+	SHA256   string    `toml:"sha256"`
+	Name     string    `toml:"name"`
+	Size     int64     `toml:"size"`
+	LastUsed time.Time `toml:"last-used"`
+}
+
+// DownloadCache keeps the most recently used dependency tarballs in a
+// cache-only layer, keyed by their SHA256, so that switching between
+// versions of the dependency does not download them again.
+type DownloadCache struct {
+	transport DownloadTransport
+	clock     chronos.Clock
+}
+
+func NewDownloadCache(transport DownloadTransport, clock chronos.Clock) DownloadCache {
+	return DownloadCache{
+		transport: transport,
+		clock:     clock,
+	}
+}
+
+// Fetch returns the tarball of the given dependency from the download cache
+// at cachePath, downloading it into the cache first if it is not there. The
+// download is verified against the SHA256 of the dependency before it is
+// cached. When the cache holds more tarballs than
+// $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE, the least recently used ones are
+// evicted.
+func (c DownloadCache) Fetch(dependency postal.Dependency, cnbPath, cachePath string) (CachedDownload, error) {
+	size := DefaultDownloadCacheSize
+	if value, ok := os.LookupEnv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE"); ok {
+		var err error
+		size, err = strconv.Atoi(value)
+		if err != nil {
+			return CachedDownload{}, fmt.Errorf("failed to parse $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE: %w", err)
+		}
+
+		if size < 1 {
+			return CachedDownload{}, fmt.Errorf("failed to parse $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE: must be at least 1, got %d", size)
+		}
+	}
+
+	tarballs, err := readDownloadCacheIndex(cachePath)
+	if err != nil {
+		return CachedDownload{}, err
+	}
+
+	for i, tarball := range tarballs {
+		if tarball.SHA256 != dependency.SHA256 {
+			continue
+		}
+
+		path := filepath.Join(cachePath, tarball.SHA256, tarball.Name)
+		info, err := os.Stat(path)
+		if err != nil || info.Size() != tarball.Size {
+			// the tarball is missing or incomplete, so it is downloaded again
+			tarballs = append(tarballs[:i], tarballs[i+1:]...)
+			break
+		}
+
+		tarballs[i].LastUsed = c.clock.Now()
+
+		err = writeDownloadCacheIndex(cachePath, tarballs)
+		if err != nil {
+			return CachedDownload{}, err
+		}
+
+		return CachedDownload{
+			Path: path,
+			Size: tarball.Size,
+			Hit:  true,
+		}, nil
+	}
+
+	tarball, err := c.download(dependency, cnbPath, cachePath)
+	if err != nil {
+		return CachedDownload{}, err
+	}
+
+	tarballs = append(tarballs, tarball)
+
+	sort.SliceStable(tarballs, func(i, j int) bool {
+		return tarballs[i].LastUsed.After(tarballs[j].LastUsed)
+	})
+
+	var evicted []string
+	for len(tarballs) > size {
+		oldest := tarballs[len(tarballs)-1]
+		tarballs = tarballs[:len(tarballs)-1]
+
+		err = os.RemoveAll(filepath.Join(cachePath, oldest.SHA256))
+		if err != nil {
+			return CachedDownload{}, fmt.Errorf("failed to evict %s from the download cache: %w", oldest.SHA256, err)
+		}
+
+		evicted = append(evicted, oldest.SHA256)
+	}
+
+	err = writeDownloadCacheIndex(cachePath, tarballs)
+	if err != nil {
+		return CachedDownload{}, err
+	}
+
+	return CachedDownload{
+		Path:    filepath.Join(cachePath, tarball.SHA256, tarball.Name),
+		Size:    tarball.Size,
+		Evicted: evicted,
+	}, nil
+}
+
+// download fetches the tarball of the dependency into the cache, keeping
+// the name of the tarball so that it can be installed from the cache just
+// like from its URI.
+func (c DownloadCache) download(dependency postal.Dependency, cnbPath, cachePath string) (cachedTarball, error) {
+	dir := filepath.Join(cachePath, dependency.SHA256)
+
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return cachedTarball{}, fmt.Errorf("failed to clear %s from the download cache: %w", dependency.SHA256, err)
+	}
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return cachedTarball{}, fmt.Errorf("failed to create download cache: %w", err)
+	}
+
+	bundle, err := c.transport.Drop(cnbPath, dependency.URI)
+	if err != nil {
+		os.RemoveAll(dir)
+		return cachedTarball{}, fmt.Errorf("failed to fetch dependency: %w", err)
+	}
+	defer bundle.Close()
+
+	tarball := cachedTarball{
+		SHA256:   dependency.SHA256,
+		Name:     filepath.Base(dependency.URI),
+		LastUsed: c.clock.Now(),
+	}
+
+	file, err := os.Create(filepath.Join(dir, tarball.Name))
+	if err != nil {
+		os.RemoveAll(dir)
+		return cachedTarball{}, fmt.Errorf("failed to create download cache entry: %w", err)
+	}
+	defer file.Close()
+
+	tarball.Size, err = io.Copy(file, cargo.NewValidatedReader(bundle, dependency.SHA256))
+	if err != nil {
+		os.RemoveAll(dir)
+		if err == cargo.ChecksumValidationError {
+			return cachedTarball{}, fmt.Errorf("failed to validate dependency: checksum does not match %s", dependency.SHA256)
+		}
+
+		return cachedTarball{}, fmt.Errorf("failed to fetch dependency: %w", err)
+	}
+
+	return tarball, nil
+}
+
+func readDownloadCacheIndex(cachePath string) ([]cachedTarball, error) {
+	var index struct {
+		Tarballs []cachedTarball `toml:"tarballs"`
+	}
+
+	_, err := toml.DecodeFile(filepath.Join(cachePath, downloadCacheIndex), &index)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to parse download cache index: %w", err)
+	}
+
+	return index.Tarballs, nil
+}
+
+func writeDownloadCacheIndex(cachePath string, tarballs []cachedTarball) error {
+	err := os.MkdirAll(cachePath, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write download cache index: %w", err)
+	}
+
+	file, err := ioutil.TempFile(cachePath, downloadCacheIndex)
+	if err != nil {
+		return fmt.Errorf("failed to write download cache index: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err = toml.NewEncoder(file).Encode(map[string]interface{}{
+		"tarballs": tarballs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write download cache index: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write download cache index: %w", err)
+	}
+
+	err = os.Rename(file.Name(), filepath.Join(cachePath, downloadCacheIndex))
+	if err != nil {
+		return fmt.Errorf("failed to write download cache index: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package dotnetcoreaspnet_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/paketo-buildpacks/dotnet-core-aspnet/fakes"
+	"github.com/paketo-buildpacks/packit/chronos"
+	"github.com/paketo-buildpacks/packit/postal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDownloadCache(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cachePath string
+		now       time.Time
+		transport *fakes.DownloadTransport
+		cache     dotnetcoreaspnet.DownloadCache
+
+		dependencyFor func(content string) postal.Dependency
+	)
+
+	it.Before(func() {
+		var err error
+		cachePath, err = ioutil.TempDir("", "downloads")
+		Expect(err).NotTo(HaveOccurred())
+
+		contents := map[string]string{}
+		dependencyFor = func(content string) postal.Dependency {
+			sum := sha256.Sum256([]byte(content))
+			sha := hex.EncodeToString(sum[:])
+			uri := "https://deps.example.com/dotnet-aspnetcore/" + content + ".tar.gz"
+			contents[uri] = content
+
+			return postal.Dependency{
+				ID:     "dotnet-aspnetcore",
+				SHA256: sha,
+				URI:    uri,
+			}
+		}
+
+		transport = &fakes.DownloadTransport{}
+		transport.DropCall.Stub = func(root, uri string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(contents[uri])), nil
+		}
+
+		now = time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)
+		clock := chronos.NewClock(func() time.Time {
+			return now
+		})
+
+		cache = dotnetcoreaspnet.NewDownloadCache(transport, clock)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cachePath)).To(Succeed())
+	})
+
+	context("Fetch", func() {
+		it("downloads the tarball into the cache", func() {
+			dependency := dependencyFor("some-content")
+
+			download, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(download).To(Equal(dotnetcoreaspnet.CachedDownload{
+				Path: filepath.Join(cachePath, dependency.SHA256, "some-content.tar.gz"),
+				Size: int64(len("some-content")),
+			}))
+
+			Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
+			Expect(transport.DropCall.Receives.Uri).To(Equal(dependency.URI))
+
+			content, err := ioutil.ReadFile(download.Path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+
+		context("when the tarball is in the cache", func() {
+			it("returns it without downloading it", func() {
+				dependency := dependencyFor("some-content")
+
+				_, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				download, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(download).To(Equal(dotnetcoreaspnet.CachedDownload{
+					Path: filepath.Join(cachePath, dependency.SHA256, "some-content.tar.gz"),
+					Size: int64(len("some-content")),
+					Hit:  true,
+				}))
+
+				Expect(transport.DropCall.CallCount).To(Equal(1))
+			})
+
+			context("when the cached tarball is missing", func() {
+				it("downloads it again", func() {
+					dependency := dependencyFor("some-content")
+
+					_, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(os.RemoveAll(filepath.Join(cachePath, dependency.SHA256))).To(Succeed())
+
+					download, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(download.Hit).To(BeFalse())
+					Expect(download.Path).To(BeARegularFile())
+
+					Expect(transport.DropCall.CallCount).To(Equal(2))
+				})
+			})
+		})
+
+		context("when the cache is full", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE", "2")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE")).To(Succeed())
+			})
+
+			it("evicts the least recently used tarball", func() {
+				first := dependencyFor("first-content")
+				second := dependencyFor("second-content")
+				third := dependencyFor("third-content")
+
+				_, err := cache.Fetch(first, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				now = now.Add(time.Hour)
+				_, err = cache.Fetch(second, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				now = now.Add(time.Hour)
+				download, err := cache.Fetch(first, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(download.Hit).To(BeTrue())
+
+				now = now.Add(time.Hour)
+				download, err = cache.Fetch(third, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(download.Evicted).To(Equal([]string{second.SHA256}))
+
+				Expect(filepath.Join(cachePath, first.SHA256)).To(BeADirectory())
+				Expect(filepath.Join(cachePath, second.SHA256)).NotTo(BeADirectory())
+				Expect(filepath.Join(cachePath, third.SHA256)).To(BeADirectory())
+
+				download, err = cache.Fetch(second, "some-cnb-path", cachePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(download.Hit).To(BeFalse())
+				Expect(download.Evicted).To(Equal([]string{first.SHA256}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE is not a number", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE", "some-size")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := cache.Fetch(dependencyFor("some-content"), "some-cnb-path", cachePath)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE")))
+				})
+			})
+
+			context("when $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE is less than 1", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE", "0")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := cache.Fetch(dependencyFor("some-content"), "some-cnb-path", cachePath)
+					Expect(err).To(MatchError("failed to parse $BP_DOTNET_ASPNET_DOWNLOAD_CACHE_SIZE: must be at least 1, got 0"))
+				})
+			})
+
+			context("when the cache index cannot be parsed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(cachePath, "index.toml"), []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := cache.Fetch(dependencyFor("some-content"), "some-cnb-path", cachePath)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse download cache index")))
+				})
+			})
+
+			context("when the tarball cannot be downloaded", func() {
+				it.Before(func() {
+					transport.DropCall.Stub = nil
+					transport.DropCall.Returns.Error = errors.New("some-error")
+				})
+
+				it("returns an error", func() {
+					dependency := dependencyFor("some-content")
+
+					_, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+					Expect(err).To(MatchError("failed to fetch dependency: some-error"))
+
+					Expect(filepath.Join(cachePath, dependency.SHA256)).NotTo(BeADirectory())
+				})
+			})
+
+			context("when the checksum of the tarball does not match", func() {
+				it("returns an error and does not cache the tarball", func() {
+					dependency := dependencyFor("some-content")
+					dependency.SHA256 = "some-other-sha"
+
+					_, err := cache.Fetch(dependency, "some-cnb-path", cachePath)
+					Expect(err).To(MatchError("failed to validate dependency: checksum does not match some-other-sha"))
+
+					Expect(filepath.Join(cachePath, "some-other-sha")).NotTo(BeADirectory())
+				})
+			})
+		})
+	})
+}
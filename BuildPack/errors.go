@@ -0,0 +1,233 @@
+package dotnetcoreaspnet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/postal"
+)
+
+// NoMatchingVersionError is returned when none of the versions of a
+// dependency that are available for the stack satisfy the requested version.
+type NoMatchingVersionError struct {
+	ID                string
+	Version           string
+	Source            string
+	Stack             string
+	AvailableVersions []string
+	Suggestion        string
+	Err               error
+}
+
+func (e NoMatchingVersionError) Error() string {
+	return fmt.Sprintf("failed to satisfy %q dependency version %q from %s: no compatible versions on %q stack. Supported versions are: [%s]", e.ID, e.Version, e.Source, e.Stack, strings.Join(e.AvailableVersions, ", "))
+}
+
+// Unwrap returns the error of the resolver that failed to resolve the
+// version.
+func (e NoMatchingVersionError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedStackError is returned when no version of a dependency is
+// available for the stack at all.
+type UnsupportedStackError struct {
+	ID                string
+	Version           string
+	Source            string
+	Stack             string
+	AvailableVersions []string
+	SupportedStacks   []string
+	Suggestion        string
+	Err               error
+}
+
+func (e UnsupportedStackError) Error() string {
+	return fmt.Sprintf("no version of %q is available on %q stack. Supported stacks are: [%s]", e.ID, e.Stack, strings.Join(e.SupportedStacks, ", "))
+}
+
+func (e UnsupportedStackError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedArchitectureError is returned when versions of a dependency are
+// available for the stack, but none of them for the architecture.
+type UnsupportedArchitectureError struct {
+	ID                     string
+	Version                string
+	Source                 string
+	Stack                  string
+	Arch                   string
+	SupportedArchitectures []string
+	Suggestion             string
+	Err                    error
+}
+
+func (e UnsupportedArchitectureError) Error() string {
+	return fmt.Sprintf("no version of %q is available for the %s architecture on %q stack. Supported architectures are: [%s]", e.ID, e.Arch, e.Stack, strings.Join(e.SupportedArchitectures, ", "))
+}
+
+func (e UnsupportedArchitectureError) Unwrap() error {
+	return e.Err
+}
+
+// DeprecatedVersionError is returned when the selected version of a
+// dependency is past its deprecation date and the deprecation policy is set
+// to fail.
+type DeprecatedVersionError struct {
+	ID                string
+	Version           string
+	Source            string
+	Stack             string
+	DeprecationDate   time.Time
+	AvailableVersions []string
+	Suggestion        string
+}
+
+func (e DeprecatedVersionError) Error() string {
+	return fmt.Sprintf("version %s of %s was deprecated on %s and $BP_DOTNET_ASPNET_DEPRECATION_POLICY is set to fail", e.Version, e.ID, e.DeprecationDate.Format("2006-01-02"))
+}
+
+// CorruptLayerError is returned when a file of a cached layer does not match
+// the content manifest recorded when the layer was installed.
+type CorruptLayerError struct {
+	Path   string
+	Reason string
+}
+
+func (e CorruptLayerError) Error() string {
+	return fmt.Sprintf("%s %s", e.Path, e.Reason)
+}
+
+// invalidVersionError is returned by the resolvers when the requested
+// version, constraint, alias or policy, or a version in buildpack.toml,
+// cannot be parsed. It is not caused by the versions that are available, so
+// newResolutionError returns it as is.
+type invalidVersionError struct {
+	err error
+}
+
+func (e invalidVersionError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidVersionError) Unwrap() error {
+	return e.err
+}
+
+// newResolutionError describes why the requested version of the dependency
+// could not be resolved from the buildpack.toml at path, keeping the given
+// error of the resolver as its cause. If the requested version is invalid or
+// buildpack.toml cannot be read, the given error is returned as is.
+func newResolutionError(path, id, version, source, stack, arch string, err error) error {
+	if errors.As(err, &invalidVersionError{}) {
+		return err
+	}
+
+	dependencies, parseErr := parseDependencies(path)
+	if parseErr != nil {
+		return err
+	}
+
+	var available []string
+	stacks := map[string]bool{}
+	architectures := map[string]bool{}
+	for _, dependency := range dependencies {
+		if dependency.ID != id {
+			continue
+		}
+
+		for _, s := range dependency.Stacks {
+			stacks[s] = true
+		}
+
+		if stacksInclude(dependency.Stacks, stack) {
+			architectures[dependency.architecture()] = true
+		}
+
+		if dependency.availableFor(stack, arch) {
+			available = append(available, dependency.Version)
+		}
+	}
+
+	if len(architectures) == 0 {
+		return UnsupportedStackError{
+			ID:              id,
+			Version:         version,
+			Source:          source,
+			Stack:           stack,
+			SupportedStacks: sortedKeys(stacks),
+			Suggestion:      "Build the app on one of the supported stacks.",
+			Err:             err,
+		}
+	}
+
+	if len(available) == 0 {
+		return UnsupportedArchitectureError{
+			ID:                     id,
+			Version:                version,
+			Source:                 source,
+			Stack:                  stack,
+			Arch:                   arch,
+			SupportedArchitectures: sortedKeys(architectures),
+			Suggestion:             "Build the app on a node of one of the supported architectures, or set $BP_DOTNET_ASPNET_ARCH to cross-build for one.",
+			Err:                    err,
+		}
+	}
+
+	return NoMatchingVersionError{
+		ID:                id,
+		Version:           version,
+		Source:            source,
+		Stack:             stack,
+		AvailableVersions: available,
+		Suggestion:        suggestVersionFix(source),
+		Err:               err,
+	}
+}
+
+// newDeprecatedVersionError describes the deprecated dependency, suggesting
+// the versions available for the stack and architecture that are not
+// deprecated as of now.
+func newDeprecatedVersionError(path, source, stack, arch string, dependency postal.Dependency, now time.Time) DeprecatedVersionError {
+	var available []string
+	dependencies, err := parseDependencies(path)
+	if err == nil {
+		for _, d := range dependencies {
+			if d.ID == dependency.ID && d.availableFor(stack, arch) && (d.DeprecationDate.IsZero() || d.DeprecationDate.After(now)) {
+				available = append(available, d.Version)
+			}
+		}
+	}
+
+	return DeprecatedVersionError{
+		ID:                dependency.ID,
+		Version:           dependency.Version,
+		Source:            source,
+		Stack:             stack,
+		DeprecationDate:   dependency.DeprecationDate,
+		AvailableVersions: available,
+		Suggestion:        suggestVersionFix(source),
+	}
+}
+
+func suggestVersionFix(source string) string {
+	switch {
+	case source == "BP_DOTNET_FRAMEWORK_VERSION" || source == "RUNTIME_VERSION":
+		return fmt.Sprintf("Set $%s to one of the available versions.", source)
+	case source == "buildpack.yml":
+		return "Set dotnet-framework.version in buildpack.yml to one of the available versions, or use $BP_DOTNET_FRAMEWORK_VERSION instead."
+	case source == "global.json":
+		return "Pin an SDK in global.json that ships one of the available versions, or set $BP_DOTNET_FRAMEWORK_VERSION."
+	case source == "runtimeconfig.json":
+		return "Publish the app against one of the available versions of Microsoft.AspNetCore.App, or set $BP_DOTNET_FRAMEWORK_VERSION."
+	case source == LockfileName:
+		return fmt.Sprintf("Remove %s to select a new version.", LockfileName)
+	case projectFileSourceRe.MatchString(source):
+		return fmt.Sprintf("Change the TargetFramework of %s to one of the available versions, or set $BP_DOTNET_FRAMEWORK_VERSION.", source)
+	default:
+		return "Set $BP_DOTNET_FRAMEWORK_VERSION to one of the available versions."
+	}
+}
@@ -0,0 +1,39 @@
+package fakes
+
+import "sync"
+
+type AliasResolver struct {
+	ResolveCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path            string
+			Id              string
+			Alias           string
+			Stack           string
+			Arch            string
+			AllowPrerelease bool
+		}
+		Returns struct {
+			String string
+			Error  error
+		}
+		Stub func(string, string, string, string, string, bool) (string, error)
+	}
+}
+
+func (f *AliasResolver) Resolve(param1 string, param2 string, param3 string, param4 string, param5 string, param6 bool) (string, error) {
+	f.ResolveCall.mutex.Lock()
+	defer f.ResolveCall.mutex.Unlock()
+	f.ResolveCall.CallCount++
+	f.ResolveCall.Receives.Path = param1
+	f.ResolveCall.Receives.Id = param2
+	f.ResolveCall.Receives.Alias = param3
+	f.ResolveCall.Receives.Stack = param4
+	f.ResolveCall.Receives.Arch = param5
+	f.ResolveCall.Receives.AllowPrerelease = param6
+	if f.ResolveCall.Stub != nil {
+		return f.ResolveCall.Stub(param1, param2, param3, param4, param5, param6)
+	}
+	return f.ResolveCall.Returns.String, f.ResolveCall.Returns.Error
+}
@@ -0,0 +1,30 @@
+package fakes
+
+import "sync"
+
+type CompatibilityChecker struct {
+	CheckCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			LayerPath  string
+			DotnetRoot string
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, string) error
+	}
+}
+
+func (f *CompatibilityChecker) Check(param1 string, param2 string) error {
+	f.CheckCall.mutex.Lock()
+	defer f.CheckCall.mutex.Unlock()
+	f.CheckCall.CallCount++
+	f.CheckCall.Receives.LayerPath = param1
+	f.CheckCall.Receives.DotnetRoot = param2
+	if f.CheckCall.Stub != nil {
+		return f.CheckCall.Stub(param1, param2)
+	}
+	return f.CheckCall.Returns.Error
+}
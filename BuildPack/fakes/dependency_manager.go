@@ -40,12 +40,13 @@ type DependencyManager struct {
 			Id      string
 			Version string
 			Stack   string
+			Arch    string
 		}
 		Returns struct {
 			Dependency postal.Dependency
 			Error      error
 		}
-		Stub func(string, string, string, string) (postal.Dependency, error)
+		Stub func(string, string, string, string, string) (postal.Dependency, error)
 	}
 }
 
@@ -71,7 +72,7 @@ func (f *DependencyManager) Install(param1 postal.Dependency, param2 string, par
 	}
 	return f.InstallCall.Returns.Error
 }
-func (f *DependencyManager) Resolve(param1 string, param2 string, param3 string, param4 string) (postal.Dependency, error) {
+func (f *DependencyManager) Resolve(param1 string, param2 string, param3 string, param4 string, param5 string) (postal.Dependency, error) {
 	f.ResolveCall.mutex.Lock()
 	defer f.ResolveCall.mutex.Unlock()
 	f.ResolveCall.CallCount++
@@ -79,8 +80,9 @@ func (f *DependencyManager) Resolve(param1 string, param2 string, param3 string,
 	f.ResolveCall.Receives.Id = param2
 	f.ResolveCall.Receives.Version = param3
 	f.ResolveCall.Receives.Stack = param4
+	f.ResolveCall.Receives.Arch = param5
 	if f.ResolveCall.Stub != nil {
-		return f.ResolveCall.Stub(param1, param2, param3, param4)
+		return f.ResolveCall.Stub(param1, param2, param3, param4, param5)
 	}
 	return f.ResolveCall.Returns.Dependency, f.ResolveCall.Returns.Error
 }
@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"sync"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+)
+
+type DepsParser struct {
+	ParseCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Glob string
+		}
+		Returns struct {
+			DepsJSON dotnetcoreaspnet.DepsJSON
+			Error    error
+		}
+		Stub func(string) (dotnetcoreaspnet.DepsJSON, error)
+	}
+}
+
+func (f *DepsParser) Parse(param1 string) (dotnetcoreaspnet.DepsJSON, error) {
+	f.ParseCall.mutex.Lock()
+	defer f.ParseCall.mutex.Unlock()
+	f.ParseCall.CallCount++
+	f.ParseCall.Receives.Glob = param1
+	if f.ParseCall.Stub != nil {
+		return f.ParseCall.Stub(param1)
+	}
+	return f.ParseCall.Returns.DepsJSON, f.ParseCall.Returns.Error
+}
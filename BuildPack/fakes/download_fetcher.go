@@ -0,0 +1,38 @@
+package fakes
+
+import (
+	"sync"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/paketo-buildpacks/packit/postal"
+)
+
+type DownloadFetcher struct {
+	FetchCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Dependency postal.Dependency
+			CnbPath    string
+			CachePath  string
+		}
+		Returns struct {
+			CachedDownload dotnetcoreaspnet.CachedDownload
+			Error          error
+		}
+		Stub func(postal.Dependency, string, string) (dotnetcoreaspnet.CachedDownload, error)
+	}
+}
+
+func (f *DownloadFetcher) Fetch(param1 postal.Dependency, param2 string, param3 string) (dotnetcoreaspnet.CachedDownload, error) {
+	f.FetchCall.mutex.Lock()
+	defer f.FetchCall.mutex.Unlock()
+	f.FetchCall.CallCount++
+	f.FetchCall.Receives.Dependency = param1
+	f.FetchCall.Receives.CnbPath = param2
+	f.FetchCall.Receives.CachePath = param3
+	if f.FetchCall.Stub != nil {
+		return f.FetchCall.Stub(param1, param2, param3)
+	}
+	return f.FetchCall.Returns.CachedDownload, f.FetchCall.Returns.Error
+}
@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"sync"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+)
+
+type GlobalConfigParser struct {
+	ParseCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+		}
+		Returns struct {
+			GlobalJSON dotnetcoreaspnet.GlobalJSON
+			Error      error
+		}
+		Stub func(string) (dotnetcoreaspnet.GlobalJSON, error)
+	}
+}
+
+func (f *GlobalConfigParser) Parse(param1 string) (dotnetcoreaspnet.GlobalJSON, error) {
+	f.ParseCall.mutex.Lock()
+	defer f.ParseCall.mutex.Unlock()
+	f.ParseCall.CallCount++
+	f.ParseCall.Receives.Path = param1
+	if f.ParseCall.Stub != nil {
+		return f.ParseCall.Stub(param1)
+	}
+	return f.ParseCall.Returns.GlobalJSON, f.ParseCall.Returns.Error
+}
@@ -0,0 +1,52 @@
+package fakes
+
+import "sync"
+
+type LayerVerifier struct {
+	RecordCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			LayerPath string
+		}
+		Returns struct {
+			MapStringInterface map[string]interface{}
+			Error              error
+		}
+		Stub func(string) (map[string]interface{}, error)
+	}
+	VerifyCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			LayerPath string
+			Manifest  map[string]interface{}
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, map[string]interface{}) error
+	}
+}
+
+func (f *LayerVerifier) Record(param1 string) (map[string]interface{}, error) {
+	f.RecordCall.mutex.Lock()
+	defer f.RecordCall.mutex.Unlock()
+	f.RecordCall.CallCount++
+	f.RecordCall.Receives.LayerPath = param1
+	if f.RecordCall.Stub != nil {
+		return f.RecordCall.Stub(param1)
+	}
+	return f.RecordCall.Returns.MapStringInterface, f.RecordCall.Returns.Error
+}
+func (f *LayerVerifier) Verify(param1 string, param2 map[string]interface{}) error {
+	f.VerifyCall.mutex.Lock()
+	defer f.VerifyCall.mutex.Unlock()
+	f.VerifyCall.CallCount++
+	f.VerifyCall.Receives.LayerPath = param1
+	f.VerifyCall.Receives.Manifest = param2
+	if f.VerifyCall.Stub != nil {
+		return f.VerifyCall.Stub(param1, param2)
+	}
+	return f.VerifyCall.Returns.Error
+}
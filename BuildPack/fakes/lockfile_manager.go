@@ -0,0 +1,56 @@
+package fakes
+
+import (
+	"sync"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+)
+
+type LockfileManager struct {
+	ReadCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+		}
+		Returns struct {
+			Lock  dotnetcoreaspnet.Lock
+			Error error
+		}
+		Stub func(string) (dotnetcoreaspnet.Lock, error)
+	}
+	WriteCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+			Lock dotnetcoreaspnet.Lock
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, dotnetcoreaspnet.Lock) error
+	}
+}
+
+func (f *LockfileManager) Read(param1 string) (dotnetcoreaspnet.Lock, error) {
+	f.ReadCall.mutex.Lock()
+	defer f.ReadCall.mutex.Unlock()
+	f.ReadCall.CallCount++
+	f.ReadCall.Receives.Path = param1
+	if f.ReadCall.Stub != nil {
+		return f.ReadCall.Stub(param1)
+	}
+	return f.ReadCall.Returns.Lock, f.ReadCall.Returns.Error
+}
+func (f *LockfileManager) Write(param1 string, param2 dotnetcoreaspnet.Lock) error {
+	f.WriteCall.mutex.Lock()
+	defer f.WriteCall.mutex.Unlock()
+	f.WriteCall.CallCount++
+	f.WriteCall.Receives.Path = param1
+	f.WriteCall.Receives.Lock = param2
+	if f.WriteCall.Stub != nil {
+		return f.WriteCall.Stub(param1, param2)
+	}
+	return f.WriteCall.Returns.Error
+}
@@ -0,0 +1,35 @@
+package fakes
+
+import (
+	"sync"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+)
+
+type MappingResolver struct {
+	FindDependencyMappingCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Sha256      string
+			PlatformDir string
+		}
+		Returns struct {
+			DependencyMapping dotnetcoreaspnet.DependencyMapping
+			Error             error
+		}
+		Stub func(string, string) (dotnetcoreaspnet.DependencyMapping, error)
+	}
+}
+
+func (f *MappingResolver) FindDependencyMapping(param1 string, param2 string) (dotnetcoreaspnet.DependencyMapping, error) {
+	f.FindDependencyMappingCall.mutex.Lock()
+	defer f.FindDependencyMappingCall.mutex.Unlock()
+	f.FindDependencyMappingCall.CallCount++
+	f.FindDependencyMappingCall.Receives.Sha256 = param1
+	f.FindDependencyMappingCall.Receives.PlatformDir = param2
+	if f.FindDependencyMappingCall.Stub != nil {
+		return f.FindDependencyMappingCall.Stub(param1, param2)
+	}
+	return f.FindDependencyMappingCall.Returns.DependencyMapping, f.FindDependencyMappingCall.Returns.Error
+}
@@ -0,0 +1,77 @@
+package fakes
+
+import "sync"
+
+type ProjectParser struct {
+	FindProjectFileCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Dir  string
+			Root string
+		}
+		Returns struct {
+			String string
+			Error  error
+		}
+		Stub func(string, string) (string, error)
+	}
+	IsWebProjectCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+		}
+		Returns struct {
+			Bool  bool
+			Error error
+		}
+		Stub func(string) (bool, error)
+	}
+	ParseTargetFrameworksCall struct {
+		mutex     sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path string
+			Root string
+		}
+		Returns struct {
+			StringSlice []string
+			Error       error
+		}
+		Stub func(string, string) ([]string, error)
+	}
+}
+
+func (f *ProjectParser) FindProjectFile(param1 string, param2 string) (string, error) {
+	f.FindProjectFileCall.mutex.Lock()
+	defer f.FindProjectFileCall.mutex.Unlock()
+	f.FindProjectFileCall.CallCount++
+	f.FindProjectFileCall.Receives.Dir = param1
+	f.FindProjectFileCall.Receives.Root = param2
+	if f.FindProjectFileCall.Stub != nil {
+		return f.FindProjectFileCall.Stub(param1, param2)
+	}
+	return f.FindProjectFileCall.Returns.String, f.FindProjectFileCall.Returns.Error
+}
+func (f *ProjectParser) IsWebProject(param1 string) (bool, error) {
+	f.IsWebProjectCall.mutex.Lock()
+	defer f.IsWebProjectCall.mutex.Unlock()
+	f.IsWebProjectCall.CallCount++
+	f.IsWebProjectCall.Receives.Path = param1
+	if f.IsWebProjectCall.Stub != nil {
+		return f.IsWebProjectCall.Stub(param1)
+	}
+	return f.IsWebProjectCall.Returns.Bool, f.IsWebProjectCall.Returns.Error
+}
+func (f *ProjectParser) ParseTargetFrameworks(param1 string, param2 string) ([]string, error) {
+	f.ParseTargetFrameworksCall.mutex.Lock()
+	defer f.ParseTargetFrameworksCall.mutex.Unlock()
+	f.ParseTargetFrameworksCall.CallCount++
+	f.ParseTargetFrameworksCall.Receives.Path = param1
+	f.ParseTargetFrameworksCall.Receives.Root = param2
+	if f.ParseTargetFrameworksCall.Stub != nil {
+		return f.ParseTargetFrameworksCall.Stub(param1, param2)
+	}
+	return f.ParseTargetFrameworksCall.Returns.StringSlice, f.ParseTargetFrameworksCall.Returns.Error
+}
@@ -0,0 +1,94 @@
+package dotnetcoreaspnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+type FrameworkCompatibilityChecker struct{}
+
+func NewFrameworkCompatibilityChecker() FrameworkCompatibilityChecker {
+	return FrameworkCompatibilityChecker{}
+}
+
+// Check verifies that the Microsoft.NETCore.App framework installed under
+// dotnetRoot can host the Microsoft.AspNetCore.App framework installed under
+// layerPath. The minimum Microsoft.NETCore.App version is read from the
+// Microsoft.AspNetCore.App.runtimeconfig.json that ships with the framework;
+// as for any framework reference, a newer minor or patch of the same major
+// version is compatible.
+func (c FrameworkCompatibilityChecker) Check(layerPath, dotnetRoot string) error {
+	configs, err := filepath.Glob(filepath.Join(layerPath, "shared", "Microsoft.AspNetCore.App", "*", "Microsoft.AspNetCore.App.runtimeconfig.json"))
+	if err != nil {
+		return err
+	}
+
+	if len(configs) == 0 {
+		return nil
+	}
+
+	aspNetVersion := filepath.Base(filepath.Dir(configs[0]))
+
+	file, err := os.Open(configs[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var config struct {
+		RuntimeOptions struct {
+			Framework struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"framework"`
+		} `json:"runtimeOptions"`
+	}
+
+	err = json.NewDecoder(file).Decode(&config)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", filepath.Base(configs[0]), err)
+	}
+
+	if config.RuntimeOptions.Framework.Name != "Microsoft.NETCore.App" {
+		return nil
+	}
+
+	required, err := semver.NewVersion(config.RuntimeOptions.Framework.Version)
+	if err != nil {
+		return fmt.Errorf("failed to parse Microsoft.NETCore.App version required by Microsoft.AspNetCore.App %s: %w", aspNetVersion, err)
+	}
+
+	runtimes, err := filepath.Glob(filepath.Join(dotnetRoot, "shared", "Microsoft.NETCore.App", "*"))
+	if err != nil {
+		return err
+	}
+
+	var installed []string
+	for _, runtime := range runtimes {
+		version := filepath.Base(runtime)
+		installed = append(installed, version)
+
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+
+		if v.Major() == required.Major() && !v.LessThan(required) {
+			return nil
+		}
+	}
+
+	if len(installed) == 0 {
+		return fmt.Errorf("Microsoft.AspNetCore.App %s requires Microsoft.NETCore.App %s or newer, but no Microsoft.NETCore.App is installed in %s", aspNetVersion, required.Original(), dotnetRoot)
+	}
+
+	sort.Strings(installed)
+
+	return fmt.Errorf("Microsoft.AspNetCore.App %s requires Microsoft.NETCore.App %s or newer, but only Microsoft.NETCore.App [%s] is installed in %s", aspNetVersion, required.Original(), strings.Join(installed, ", "), dotnetRoot)
+}
@@ -0,0 +1,126 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testFrameworkCompatibilityChecker(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layerPath  string
+		dotnetRoot string
+		checker    dotnetcoreaspnet.FrameworkCompatibilityChecker
+	)
+
+	it.Before(func() {
+		var err error
+		layerPath, err = ioutil.TempDir("", "layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		dotnetRoot, err = ioutil.TempDir("", "dotnet-root")
+		Expect(err).NotTo(HaveOccurred())
+
+		frameworkDir := filepath.Join(layerPath, "shared", "Microsoft.AspNetCore.App", "6.0.2")
+		Expect(os.MkdirAll(frameworkDir, os.ModePerm)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(frameworkDir, "Microsoft.AspNetCore.App.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "tfm": "net6.0",
+    "framework": {
+      "name": "Microsoft.NETCore.App",
+      "version": "6.0.2"
+    }
+  }
+}`), 0600)).To(Succeed())
+
+		checker = dotnetcoreaspnet.NewFrameworkCompatibilityChecker()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(layerPath)).To(Succeed())
+		Expect(os.RemoveAll(dotnetRoot)).To(Succeed())
+	})
+
+	context("Check", func() {
+		context("when the installed Microsoft.NETCore.App is the required version", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(dotnetRoot, "shared", "Microsoft.NETCore.App", "6.0.2"), os.ModePerm)).To(Succeed())
+			})
+
+			it("succeeds", func() {
+				Expect(checker.Check(layerPath, dotnetRoot)).To(Succeed())
+			})
+		})
+
+		context("when the installed Microsoft.NETCore.App is a newer version of the same major", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(dotnetRoot, "shared", "Microsoft.NETCore.App", "6.1.0"), os.ModePerm)).To(Succeed())
+			})
+
+			it("succeeds", func() {
+				Expect(checker.Check(layerPath, dotnetRoot)).To(Succeed())
+			})
+		})
+
+		context("when the layer does not contain a Microsoft.AspNetCore.App.runtimeconfig.json", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(layerPath, "shared"))).To(Succeed())
+			})
+
+			it("succeeds", func() {
+				Expect(checker.Check(layerPath, dotnetRoot)).To(Succeed())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the installed Microsoft.NETCore.App is older than required", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(dotnetRoot, "shared", "Microsoft.NETCore.App", "6.0.1"), os.ModePerm)).To(Succeed())
+					Expect(os.MkdirAll(filepath.Join(dotnetRoot, "shared", "Microsoft.NETCore.App", "5.0.14"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error with both versions", func() {
+					err := checker.Check(layerPath, dotnetRoot)
+					Expect(err).To(MatchError("Microsoft.AspNetCore.App 6.0.2 requires Microsoft.NETCore.App 6.0.2 or newer, but only Microsoft.NETCore.App [5.0.14, 6.0.1] is installed in " + dotnetRoot))
+				})
+			})
+
+			context("when the installed Microsoft.NETCore.App is a different major version", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(dotnetRoot, "shared", "Microsoft.NETCore.App", "7.0.0"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					err := checker.Check(layerPath, dotnetRoot)
+					Expect(err).To(MatchError(ContainSubstring("but only Microsoft.NETCore.App [7.0.0] is installed")))
+				})
+			})
+
+			context("when Microsoft.NETCore.App is not installed", func() {
+				it("returns an error", func() {
+					err := checker.Check(layerPath, dotnetRoot)
+					Expect(err).To(MatchError("Microsoft.AspNetCore.App 6.0.2 requires Microsoft.NETCore.App 6.0.2 or newer, but no Microsoft.NETCore.App is installed in " + dotnetRoot))
+				})
+			})
+
+			context("when the Microsoft.AspNetCore.App.runtimeconfig.json is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(layerPath, "shared", "Microsoft.AspNetCore.App", "6.0.2", "Microsoft.AspNetCore.App.runtimeconfig.json"), []byte(`{`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					err := checker.Check(layerPath, dotnetRoot)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode Microsoft.AspNetCore.App.runtimeconfig.json")))
+				})
+			})
+		})
+	})
+}
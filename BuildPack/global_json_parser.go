@@ -0,0 +1,70 @@
+package dotnetcoreaspnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type GlobalJSON struct {
+	SDKVersion  string
+	RollForward string
+}
+
+type GlobalJSONParser struct{}
+
+func NewGlobalJSONParser() GlobalJSONParser {
+	return GlobalJSONParser{}
+}
+
+// Parse returns the SDK version and roll-forward policy pinned by the
+// global.json file at the given path. If the file does not exist, an empty
+// GlobalJSON is returned.
+func (p GlobalJSONParser) Parse(path string) (GlobalJSON, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return GlobalJSON{}, nil
+		}
+		return GlobalJSON{}, err
+	}
+	defer file.Close()
+
+	var data struct {
+		SDK struct {
+			Version     string `json:"version"`
+			RollForward string `json:"rollForward"`
+		} `json:"sdk"`
+	}
+
+	err = json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		return GlobalJSON{}, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+
+	return GlobalJSON{
+		SDKVersion:  data.SDK.Version,
+		RollForward: data.SDK.RollForward,
+	}, nil
+}
+
+// RuntimeRollForward translates an SDK rollForward setting from global.json
+// into the runtime roll-forward policy with the same reach. SDK feature bands
+// and patches all ship the same runtime major and minor, so the settings that
+// stay within a feature band or minor only roll the runtime's patch.
+func RuntimeRollForward(sdkRollForward string) string {
+	switch strings.ToLower(sdkRollForward) {
+	case "minor":
+		return "Minor"
+	case "latestminor":
+		return "LatestMinor"
+	case "major":
+		return "Major"
+	case "latestmajor":
+		return "LatestMajor"
+	default:
+		return "LatestPatch"
+	}
+}
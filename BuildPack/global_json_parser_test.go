@@ -0,0 +1,92 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testGlobalJSONParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		path       string
+		parser     dotnetcoreaspnet.GlobalJSONParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = ioutil.TempDir("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(workingDir, "global.json")
+		Expect(ioutil.WriteFile(path, []byte(`{
+  "sdk": {
+    "version": "6.0.100",
+    "rollForward": "latestFeature"
+  }
+}`), 0600)).To(Succeed())
+
+		parser = dotnetcoreaspnet.NewGlobalJSONParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Parse", func() {
+		it("returns the SDK version and roll-forward policy", func() {
+			globalJSON, err := parser.Parse(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(globalJSON).To(Equal(dotnetcoreaspnet.GlobalJSON{
+				SDKVersion:  "6.0.100",
+				RollForward: "latestFeature",
+			}))
+		})
+
+		context("when the global.json does not exist", func() {
+			it.Before(func() {
+				Expect(os.Remove(path)).To(Succeed())
+			})
+
+			it("returns an empty result", func() {
+				globalJSON, err := parser.Parse(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(globalJSON).To(Equal(dotnetcoreaspnet.GlobalJSON{}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the global.json is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte(`{`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.Parse(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode global.json")))
+				})
+			})
+		})
+	})
+
+	context("RuntimeRollForward", func() {
+		it("translates SDK roll-forward settings to runtime policies", func() {
+			Expect(dotnetcoreaspnet.RuntimeRollForward("")).To(Equal("LatestPatch"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("disable")).To(Equal("LatestPatch"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("patch")).To(Equal("LatestPatch"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("latestFeature")).To(Equal("LatestPatch"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("minor")).To(Equal("Minor"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("latestMinor")).To(Equal("LatestMinor"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("major")).To(Equal("Major"))
+			Expect(dotnetcoreaspnet.RuntimeRollForward("latestMajor")).To(Equal("LatestMajor"))
+		})
+	})
+}
@@ -11,8 +11,26 @@ func TestUnitDotnetCoreAspnet(t *testing.T) {
 	suite := spec.New("dotnet-core-aspnet", spec.Report(report.Terminal{}))
 	suite("Build", testBuild)
 	suite("BuildpackYMLParser", testBuildpackYMLParser)
+	suite("DependencyMappingResolver", testDependencyMappingResolver)
+	suite("DependencyMirrorResolver", testDependencyMirrorResolver)
+	suite("DependencyService", testDependencyService)
+	suite("DeprecationPolicy", testDeprecationPolicy)
+	suite("DepsJSONParser", testDepsJSONParser)
 	suite("Detect", testDetect)
+	suite("DownloadCache", testDownloadCache)
+	suite("FrameworkCompatibilityChecker", testFrameworkCompatibilityChecker)
+	suite("GlobalJSONParser", testGlobalJSONParser)
+	suite("LayerManifest", testLayerManifest)
+	suite("Lockfile", testLockfile)
 	suite("LogEmitter", testLogEmitter)
+	suite("PrereleaseResolver", testPrereleaseResolver)
+	suite("ProjectFileParser", testProjectFileParser)
+	suite("RollForwardResolver", testRollForwardResolver)
+	suite("RuntimeConfigParser", testRuntimeConfigParser)
+	suite("Transport", testTransport)
+	suite("VersionAliasResolver", testVersionAliasResolver)
+	suite("VersionConflicts", testVersionConflicts)
+	suite("VersionSourcePriority", testVersionSourcePriority)
 	suite("DotnetRootLinker", testDotnetRootLinker)
 	suite.Run(t)
 }
@@ -0,0 +1,177 @@
+package dotnetcoreaspnet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// The depths of verification of a cached layer that
+// $BP_DOTNET_ASPNET_LAYER_VERIFICATION can select.
+const (
+	LayerVerificationNone   = "none"
+	LayerVerificationSample = "sample"
+	LayerVerificationFull   = "full"
+)
+
+// layerVerificationSampleSize is the number of files whose hashes are
+// checked by a sample verification.
+const layerVerificationSampleSize = 10
+
+// LayerManifest records the files of the Microsoft.AspNetCore.App framework
+// in a layer, so that a cached layer can be verified before it is reused.
+type LayerManifest struct{}
+
+func NewLayerManifest() LayerManifest {
+	return LayerManifest{}
+}
+
+// Record returns the content manifest of the layer at layerPath: the size
+// and SHA256 of every file under shared/Microsoft.AspNetCore.App, keyed by
+// its path relative to the layer. The manifest is stored in the layer
+// metadata.
+func (m LayerManifest) Record(layerPath string) (map[string]interface{}, error) {
+	manifest := map[string]interface{}{}
+
+	root := filepath.Join(layerPath, "shared", "Microsoft.AspNetCore.App")
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(layerPath, path)
+		if err != nil {
+			return err
+		}
+
+		manifest[filepath.ToSlash(rel)] = map[string]interface{}{
+			"size":   info.Size(),
+			"sha256": sum,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record layer content manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Verify checks the layer at layerPath against the given content manifest.
+// $BP_DOTNET_ASPNET_LAYER_VERIFICATION sets how deep the check goes:
+//
+//   - none skips it
+//   - sample, the default, checks that every file is present with the
+//     recorded size, and the hashes of the largest files
+//   - full checks the hashes of every file as well
+//
+// A CorruptLayerError is returned for the first file that does not match,
+// or if there is no manifest to verify the layer against.
+func (m LayerManifest) Verify(layerPath string, manifest map[string]interface{}) error {
+	depth := LayerVerificationSample
+	if value, ok := os.LookupEnv("BP_DOTNET_ASPNET_LAYER_VERIFICATION"); ok {
+		depth = value
+	}
+
+	switch depth {
+	case LayerVerificationNone:
+		return nil
+	case LayerVerificationSample, LayerVerificationFull:
+	default:
+		return fmt.Errorf("failed to parse $BP_DOTNET_ASPNET_LAYER_VERIFICATION: %q is not one of %s, %s or %s", depth, LayerVerificationNone, LayerVerificationSample, LayerVerificationFull)
+	}
+
+	if len(manifest) == 0 {
+		return CorruptLayerError{Path: layerPath, Reason: "has no content manifest"}
+	}
+
+	type file struct {
+		path   string
+		size   int64
+		sha256 string
+	}
+
+	var files []file
+	for path, value := range manifest {
+		entry, _ := value.(map[string]interface{})
+		size, ok := entry["size"].(int64)
+		sum, _ := entry["sha256"].(string)
+		if !ok || sum == "" {
+			return CorruptLayerError{Path: path, Reason: "has a malformed content manifest entry"}
+		}
+
+		files = append(files, file{path: path, size: size, sha256: sum})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].size != files[j].size {
+			return files[i].size > files[j].size
+		}
+
+		return files[i].path < files[j].path
+	})
+
+	for i, f := range files {
+		info, err := os.Stat(filepath.Join(layerPath, filepath.FromSlash(f.path)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return CorruptLayerError{Path: f.path, Reason: "is missing"}
+			}
+
+			return err
+		}
+
+		if info.Size() != f.size {
+			return CorruptLayerError{Path: f.path, Reason: fmt.Sprintf("has size %d, expected %d", info.Size(), f.size)}
+		}
+
+		if depth == LayerVerificationSample && i >= layerVerificationSampleSize {
+			continue
+		}
+
+		sum, err := hashFile(filepath.Join(layerPath, filepath.FromSlash(f.path)))
+		if err != nil {
+			return err
+		}
+
+		if sum != f.sha256 {
+			return CorruptLayerError{Path: f.path, Reason: fmt.Sprintf("has SHA256 %s, expected %s", sum, f.sha256)}
+		}
+	}
+
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
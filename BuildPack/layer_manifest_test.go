@@ -0,0 +1,204 @@
+package dotnetcoreaspnet_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLayerManifest(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layerPath     string
+		frameworkPath string
+		layerManifest dotnetcoreaspnet.LayerManifest
+	)
+
+	it.Before(func() {
+		var err error
+		layerPath, err = ioutil.TempDir("", "layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		frameworkPath = filepath.Join(layerPath, "shared", "Microsoft.AspNetCore.App", "6.0.2")
+		Expect(os.MkdirAll(frameworkPath, os.ModePerm)).To(Succeed())
+
+		// the files are of different sizes so that the largest ones, which a
+		// sample verification hashes, are known
+		for i := 1; i <= 12; i++ {
+			content := strings.Repeat("x", i*10)
+			Expect(ioutil.WriteFile(filepath.Join(frameworkPath, fmt.Sprintf("file-%02d.dll", i)), []byte(content), 0600)).To(Succeed())
+		}
+
+		Expect(ioutil.WriteFile(filepath.Join(layerPath, "LICENSE.txt"), []byte("some-license"), 0600)).To(Succeed())
+
+		layerManifest = dotnetcoreaspnet.NewLayerManifest()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(layerPath)).To(Succeed())
+	})
+
+	context("Record", func() {
+		it("records the size and hash of every framework file", func() {
+			manifest, err := layerManifest.Record(layerPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manifest).To(HaveLen(12))
+			Expect(manifest).To(HaveKeyWithValue("shared/Microsoft.AspNetCore.App/6.0.2/file-01.dll", map[string]interface{}{
+				"size":   int64(10),
+				"sha256": "fc11d6f28e59d3cc33c0b14ceb644bf0902ebd63d61218dffe9e7dac7c254542",
+			}))
+			Expect(manifest).NotTo(HaveKey("LICENSE.txt"))
+		})
+
+		context("when the layer has no framework", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(layerPath, "shared"))).To(Succeed())
+			})
+
+			it("returns an empty manifest", func() {
+				manifest, err := layerManifest.Record(layerPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifest).To(BeEmpty())
+			})
+		})
+	})
+
+	context("Verify", func() {
+		var manifest map[string]interface{}
+
+		it.Before(func() {
+			var err error
+			manifest, err = layerManifest.Record(layerPath)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("succeeds when the layer matches the manifest", func() {
+			Expect(layerManifest.Verify(layerPath, manifest)).To(Succeed())
+		})
+
+		context("when a file is missing", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(frameworkPath, "file-01.dll"))).To(Succeed())
+			})
+
+			it("returns a CorruptLayerError", func() {
+				err := layerManifest.Verify(layerPath, manifest)
+				Expect(err).To(MatchError(dotnetcoreaspnet.CorruptLayerError{
+					Path:   "shared/Microsoft.AspNetCore.App/6.0.2/file-01.dll",
+					Reason: "is missing",
+				}))
+			})
+		})
+
+		context("when a file is truncated", func() {
+			it.Before(func() {
+				Expect(os.Truncate(filepath.Join(frameworkPath, "file-01.dll"), 5)).To(Succeed())
+			})
+
+			it("returns a CorruptLayerError", func() {
+				err := layerManifest.Verify(layerPath, manifest)
+				Expect(err).To(MatchError("shared/Microsoft.AspNetCore.App/6.0.2/file-01.dll has size 5, expected 10"))
+			})
+		})
+
+		context("when one of the largest files is corrupted", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(frameworkPath, "file-12.dll"), []byte(strings.Repeat("y", 120)), 0600)).To(Succeed())
+			})
+
+			it("returns a CorruptLayerError", func() {
+				err := layerManifest.Verify(layerPath, manifest)
+				Expect(err).To(BeAssignableToTypeOf(dotnetcoreaspnet.CorruptLayerError{}))
+				Expect(err).To(MatchError(ContainSubstring("shared/Microsoft.AspNetCore.App/6.0.2/file-12.dll has SHA256")))
+			})
+		})
+
+		context("when a file outside the sample is corrupted", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(frameworkPath, "file-01.dll"), []byte(strings.Repeat("y", 10)), 0600)).To(Succeed())
+			})
+
+			it("does not notice it", func() {
+				Expect(layerManifest.Verify(layerPath, manifest)).To(Succeed())
+			})
+
+			context("when $BP_DOTNET_ASPNET_LAYER_VERIFICATION is full", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_ASPNET_LAYER_VERIFICATION", "full")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_ASPNET_LAYER_VERIFICATION")).To(Succeed())
+				})
+
+				it("returns a CorruptLayerError", func() {
+					err := layerManifest.Verify(layerPath, manifest)
+					Expect(err).To(BeAssignableToTypeOf(dotnetcoreaspnet.CorruptLayerError{}))
+					Expect(err).To(MatchError(ContainSubstring("shared/Microsoft.AspNetCore.App/6.0.2/file-01.dll has SHA256")))
+				})
+			})
+		})
+
+		context("when $BP_DOTNET_ASPNET_LAYER_VERIFICATION is none", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_ASPNET_LAYER_VERIFICATION", "none")).To(Succeed())
+				Expect(os.RemoveAll(filepath.Join(layerPath, "shared"))).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_ASPNET_LAYER_VERIFICATION")).To(Succeed())
+			})
+
+			it("skips the verification", func() {
+				Expect(layerManifest.Verify(layerPath, manifest)).To(Succeed())
+				Expect(layerManifest.Verify(layerPath, nil)).To(Succeed())
+			})
+		})
+
+		context("when there is no manifest", func() {
+			it("returns a CorruptLayerError", func() {
+				err := layerManifest.Verify(layerPath, nil)
+				Expect(err).To(MatchError(dotnetcoreaspnet.CorruptLayerError{
+					Path:   layerPath,
+					Reason: "has no content manifest",
+				}))
+			})
+		})
+
+		context("when a manifest entry is malformed", func() {
+			it("returns a CorruptLayerError", func() {
+				err := layerManifest.Verify(layerPath, map[string]interface{}{
+					"shared/Microsoft.AspNetCore.App/6.0.2/file-01.dll": "some-entry",
+				})
+				Expect(err).To(MatchError("shared/Microsoft.AspNetCore.App/6.0.2/file-01.dll has a malformed content manifest entry"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when $BP_DOTNET_ASPNET_LAYER_VERIFICATION is invalid", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_ASPNET_LAYER_VERIFICATION", "some-depth")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_DOTNET_ASPNET_LAYER_VERIFICATION")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					err := layerManifest.Verify(layerPath, manifest)
+					Expect(err).To(MatchError(`failed to parse $BP_DOTNET_ASPNET_LAYER_VERIFICATION: "some-depth" is not one of none, sample or full`))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,64 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LockfileName is the name of the lockfile, in the root of the app source,
+// that records the dependency selected when $BP_DOTNET_ASPNET_LOCK is set.
+const LockfileName = "dotnet-aspnet.lock"
+
+type Lock struct {
+	ID      string `toml:"id"`
+	Version string `toml:"version"`
+	SHA256  string `toml:"sha256"`
+	Stack   string `toml:"stack"`
+	Arch    string `toml:"arch,omitempty"`
+}
+
+type Lockfile struct{}
+
+func NewLockfile() Lockfile {
+	return Lockfile{}
+}
+
+// Read returns the dependency locked by the lockfile at path. If there is no
+// lockfile, an empty Lock is returned.
+func (l Lockfile) Read(path string) (Lock, error) {
+	var lockfile struct {
+		Dependency Lock `toml:"dependency"`
+	}
+
+	_, err := toml.DecodeFile(path, &lockfile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Lock{}, nil
+		}
+
+		return Lock{}, fmt.Errorf("failed to parse %s: %w", LockfileName, err)
+	}
+
+	return lockfile.Dependency, nil
+}
+
+// Write records the given dependency in the lockfile at path, replacing any
+// existing lockfile.
+func (l Lockfile) Write(path string, lock Lock) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockfileName, err)
+	}
+	defer file.Close()
+
+	err = toml.NewEncoder(file).Encode(map[string]interface{}{
+		"dependency": lock,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockfileName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLockfile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		path       string
+		lockfile   dotnetcoreaspnet.Lockfile
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = ioutil.TempDir("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(workingDir, "dotnet-aspnet.lock")
+
+		lockfile = dotnetcoreaspnet.NewLockfile()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	it("reads back the lock that was written", func() {
+		lock := dotnetcoreaspnet.Lock{
+			ID:      "dotnet-aspnetcore",
+			Version: "6.0.2",
+			SHA256:  "some-sha",
+			Stack:   "some-stack",
+		}
+
+		Expect(lockfile.Write(path, lock)).To(Succeed())
+
+		content, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("[dependency]"))
+		Expect(string(content)).To(ContainSubstring(`version = "6.0.2"`))
+
+		result, err := lockfile.Read(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(lock))
+	})
+
+	context("Read", func() {
+		context("when there is no lockfile", func() {
+			it("returns an empty lock", func() {
+				lock, err := lockfile.Read(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lock).To(Equal(dotnetcoreaspnet.Lock{}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the lockfile is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := lockfile.Read(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse dotnet-aspnet.lock")))
+				})
+			})
+		})
+	})
+
+	context("Write", func() {
+		context("failure cases", func() {
+			context("when the lockfile cannot be created", func() {
+				it("returns an error", func() {
+					err := lockfile.Write(filepath.Join(workingDir, "missing", "dotnet-aspnet.lock"), dotnetcoreaspnet.Lock{})
+					Expect(err).To(MatchError(ContainSubstring("failed to write dotnet-aspnet.lock")))
+				})
+			})
+		})
+	})
+}
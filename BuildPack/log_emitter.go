@@ -1,7 +1,10 @@
 package dotnetcoreaspnet
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/paketo-buildpacks/packit"
@@ -21,11 +24,180 @@ func NewLogEmitter(output io.Writer) LogEmitter {
 	}
 }
 
+// SelectedDependency logs the selected dependency, along with the version
+// alias it was resolved from, if any. Deprecation warnings are left to
+// Deprecation so that their window can be configured.
 func (e LogEmitter) SelectedDependency(entry packit.BuildpackPlanEntry, dependency postal.Dependency, now time.Time) {
+	if alias, _ := entry.Metadata["version"].(string); IsVersionAlias(alias) {
+		source, ok := entry.Metadata["version-source"].(string)
+		if !ok {
+			source = "<unknown>"
+		}
+
+		e.Subprocess("Selected %s version (using %s): %s (resolved from %q)", dependency.ID, source, dependency.Version, alias)
+		e.Break()
+		return
+	}
+
 	dependency.Name = dependency.ID
+	dependency.DeprecationDate = time.Time{}
 	e.Emitter.SelectedDependency(entry, dependency, now)
 }
 
+// Deprecation warns when the given dependency is deprecated, or will be
+// within the given window.
+func (e LogEmitter) Deprecation(dependency postal.Dependency, now time.Time, window time.Duration) {
+	deprecationDate := dependency.DeprecationDate
+	switch {
+	case deprecationDate.IsZero() || deprecationDate.Add(-window).After(now):
+		return
+	case deprecationDate.After(now):
+		e.Subprocess("WARNING: Version %s of %s will be deprecated after %s.", dependency.Version, dependency.ID, deprecationDate.Format("2006-01-02"))
+		e.Subprocess("Migrate your application to a supported version of %s before this time.", dependency.ID)
+	default:
+		e.Subprocess("WARNING: Version %s of %s was deprecated on %s.", dependency.Version, dependency.ID, deprecationDate.Format("2006-01-02"))
+		e.Subprocess("Migrate your application to a supported version of %s.", dependency.ID)
+	}
+	e.Break()
+}
+
+// Candidates logs the candidate version sources, followed by the target
+// framework that was selected for any project file that targets several.
+func (e LogEmitter) Candidates(entries []packit.BuildpackPlanEntry) {
+	e.Emitter.Candidates(entries)
+
+	var logged bool
+	for _, entry := range entries {
+		frameworks, ok := entry.Metadata["target-frameworks"].(string)
+		if !ok {
+			continue
+		}
+
+		source, _ := entry.Metadata["version-source"].(string)
+		framework, _ := entry.Metadata["target-framework"].(string)
+		e.Subprocess("%s targets multiple frameworks (%s), selected %s", source, strings.ReplaceAll(frameworks, ";", ", "), framework)
+		logged = true
+	}
+
+	if logged {
+		e.Subprocess("Set $BP_DOTNET_TARGET_FRAMEWORK to select a different target framework.")
+		e.Break()
+	}
+}
+
+// VersionConflicts warns that the given entries require conflicting
+// versions, and that the version of the selected entry is used.
+func (e LogEmitter) VersionConflicts(selected packit.BuildpackPlanEntry, conflicts []packit.BuildpackPlanEntry) {
+	e.Subprocess("WARNING: The version sources require conflicting %s versions:", selected.Name)
+
+	var maxLen int
+	for _, entry := range conflicts {
+		source, _ := entry.Metadata["version-source"].(string)
+		if len(source) > maxLen {
+			maxLen = len(source)
+		}
+	}
+
+	for _, entry := range conflicts {
+		source, _ := entry.Metadata["version-source"].(string)
+		version, _ := entry.Metadata["version"].(string)
+		e.Action("%-*s -> %q", maxLen, source, version)
+	}
+
+	source, _ := selected.Metadata["version-source"].(string)
+	e.Subprocess("Using the version from %s, which has the highest priority.", source)
+	e.Subprocess("Set $BP_DOTNET_ASPNET_STRICT_VERSIONS to true to fail the build instead.")
+	e.Break()
+}
+
+// ResolutionError explains a NoMatchingVersionError, UnsupportedStackError,
+// UnsupportedArchitectureError or DeprecatedVersionError, and suggests how
+// to fix it. Other errors are not logged.
+func (e LogEmitter) ResolutionError(err error) {
+	var (
+		noMatchingVersion NoMatchingVersionError
+		unsupportedStack  UnsupportedStackError
+		unsupportedArch   UnsupportedArchitectureError
+		deprecatedVersion DeprecatedVersionError
+	)
+
+	switch {
+	case errors.As(err, &noMatchingVersion):
+		e.Subprocess("ERROR: No version of %s matches the requested version", noMatchingVersion.ID)
+		e.Action("Requested version: %q (using %s)", noMatchingVersion.Version, versionSourceOrUnknown(noMatchingVersion.Source))
+		e.Action("Available versions on %s: %s", noMatchingVersion.Stack, strings.Join(noMatchingVersion.AvailableVersions, ", "))
+		e.Action("Suggested fix: %s", noMatchingVersion.Suggestion)
+
+	case errors.As(err, &unsupportedStack):
+		e.Subprocess("ERROR: %s is not available on the %s stack", unsupportedStack.ID, unsupportedStack.Stack)
+		e.Action("Requested version: %q (using %s)", unsupportedStack.Version, versionSourceOrUnknown(unsupportedStack.Source))
+		e.Action("Supported stacks: %s", strings.Join(unsupportedStack.SupportedStacks, ", "))
+		e.Action("Suggested fix: %s", unsupportedStack.Suggestion)
+
+	case errors.As(err, &unsupportedArch):
+		e.Subprocess("ERROR: %s is not available for the %s architecture on the %s stack", unsupportedArch.ID, unsupportedArch.Arch, unsupportedArch.Stack)
+		e.Action("Requested version: %q (using %s)", unsupportedArch.Version, versionSourceOrUnknown(unsupportedArch.Source))
+		e.Action("Supported architectures: %s", strings.Join(unsupportedArch.SupportedArchitectures, ", "))
+		e.Action("Suggested fix: %s", unsupportedArch.Suggestion)
+
+	case errors.As(err, &deprecatedVersion):
+		e.Subprocess("ERROR: Version %s of %s was deprecated on %s", deprecatedVersion.Version, deprecatedVersion.ID, deprecatedVersion.DeprecationDate.Format("2006-01-02"))
+		e.Action("Requested by: %s", versionSourceOrUnknown(deprecatedVersion.Source))
+		e.Action("Supported versions on %s: %s", deprecatedVersion.Stack, strings.Join(deprecatedVersion.AvailableVersions, ", "))
+		e.Action("Suggested fix: %s", deprecatedVersion.Suggestion)
+
+	default:
+		return
+	}
+
+	e.Break()
+}
+
+func versionSourceOrUnknown(source string) string {
+	if source == "" {
+		return "<unknown>"
+	}
+	return source
+}
+
+// DownloadProgress logs how much of a dependency has been downloaded, out of
+// its total size if it is known, and the average throughput so far.
+func (e LogEmitter) DownloadProgress(received, total int64, elapsed time.Duration) {
+	var throughput int64
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		throughput = int64(float64(received) / seconds)
+	}
+
+	if total > 0 {
+		e.Action("Downloaded %s of %s (%d%%) at %s/s", formatBytes(received), formatBytes(total), received*100/total, formatBytes(throughput))
+		return
+	}
+
+	e.Action("Downloaded %s at %s/s", formatBytes(received), formatBytes(throughput))
+}
+
+// DownloadRetry logs that a download failed and will be retried after the
+// given delay.
+func (e LogEmitter) DownloadRetry(attempt, attempts int, delay time.Duration, err error) {
+	e.Action("Download failed (attempt %d of %d): %s", attempt, attempts, err)
+	e.Action("Retrying in %s", delay)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
 func (l LogEmitter) Environment(env packit.Environment) {
 	l.Process("Configuring environment")
 	l.Subprocess("%s", scribe.NewFormattedMapFromEnvironment(env))
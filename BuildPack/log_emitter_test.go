@@ -2,10 +2,14 @@ package dotnetcoreaspnet_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
 	"github.com/paketo-buildpacks/packit"
+	"github.com/paketo-buildpacks/packit/postal"
 	"github.com/sclevine/spec"
 
 	. "github.com/onsi/gomega"
@@ -34,4 +38,242 @@ func testLogEmitter(t *testing.T, context spec.G, it spec.S) {
 			Expect(buffer.String()).To(ContainSubstring("    GEM_PATH -> \"/some/path\""))
 		})
 	})
+
+	context("Candidates", func() {
+		it("prints the candidate version sources", func() {
+			emitter.Candidates([]packit.BuildpackPlanEntry{
+				{
+					Metadata: map[string]interface{}{
+						"version-source": "BP_DOTNET_FRAMEWORK_VERSION",
+						"version":        "6.0.*",
+					},
+				},
+			})
+
+			Expect(buffer.String()).To(ContainSubstring("    Candidate version sources (in priority order):"))
+			Expect(buffer.String()).To(ContainSubstring(`      BP_DOTNET_FRAMEWORK_VERSION -> "6.0.*"`))
+			Expect(buffer.String()).NotTo(ContainSubstring("targets multiple frameworks"))
+		})
+
+		context("when a project file targets multiple frameworks", func() {
+			it("prints the selected target framework", func() {
+				emitter.Candidates([]packit.BuildpackPlanEntry{
+					{
+						Metadata: map[string]interface{}{
+							"version-source":    "some-app.csproj",
+							"version":           "7.0.*",
+							"target-frameworks": "net6.0;net7.0",
+							"target-framework":  "net7.0",
+						},
+					},
+				})
+
+				Expect(buffer.String()).To(ContainSubstring(`      some-app.csproj -> "7.0.*"`))
+				Expect(buffer.String()).To(ContainSubstring("    some-app.csproj targets multiple frameworks (net6.0, net7.0), selected net7.0"))
+				Expect(buffer.String()).To(ContainSubstring("    Set $BP_DOTNET_TARGET_FRAMEWORK to select a different target framework."))
+			})
+		})
+	})
+
+	context("VersionConflicts", func() {
+		it("prints the conflicting version sources and the one that is used", func() {
+			selected := packit.BuildpackPlanEntry{
+				Name: "dotnet-aspnetcore",
+				Metadata: map[string]interface{}{
+					"version-source": "BP_DOTNET_FRAMEWORK_VERSION",
+					"version":        "5.0.*",
+				},
+			}
+
+			emitter.VersionConflicts(selected, []packit.BuildpackPlanEntry{
+				selected,
+				{
+					Name: "dotnet-aspnetcore",
+					Metadata: map[string]interface{}{
+						"version-source": "some-app.csproj",
+						"version":        "6.0.*",
+					},
+				},
+			})
+
+			Expect(buffer.String()).To(ContainSubstring("    WARNING: The version sources require conflicting dotnet-aspnetcore versions:"))
+			Expect(buffer.String()).To(ContainSubstring(`      BP_DOTNET_FRAMEWORK_VERSION -> "5.0.*"`))
+			Expect(buffer.String()).To(ContainSubstring(`      some-app.csproj             -> "6.0.*"`))
+			Expect(buffer.String()).To(ContainSubstring("    Using the version from BP_DOTNET_FRAMEWORK_VERSION, which has the highest priority."))
+			Expect(buffer.String()).To(ContainSubstring("    Set $BP_DOTNET_ASPNET_STRICT_VERSIONS to true to fail the build instead."))
+		})
+	})
+
+	context("SelectedDependency", func() {
+		it("prints the selected dependency without deprecation warnings", func() {
+			now := time.Now()
+			emitter.SelectedDependency(packit.BuildpackPlanEntry{
+				Metadata: map[string]interface{}{
+					"version-source": "BP_DOTNET_FRAMEWORK_VERSION",
+				},
+			}, postal.Dependency{
+				ID:              "dotnet-aspnetcore",
+				Version:         "5.0.14",
+				DeprecationDate: now.Add(-time.Hour),
+			}, now)
+
+			Expect(buffer.String()).To(ContainSubstring("    Selected dotnet-aspnetcore version (using BP_DOTNET_FRAMEWORK_VERSION): 5.0.14"))
+			Expect(buffer.String()).NotTo(ContainSubstring("deprecated"))
+		})
+
+		context("when the version was resolved from an alias", func() {
+			it("prints the alias alongside the selected version", func() {
+				emitter.SelectedDependency(packit.BuildpackPlanEntry{
+					Metadata: map[string]interface{}{
+						"version-source": "BP_DOTNET_FRAMEWORK_VERSION",
+						"version":        "6",
+					},
+				}, postal.Dependency{
+					ID:      "dotnet-aspnetcore",
+					Version: "6.0.2",
+				}, time.Now())
+
+				Expect(buffer.String()).To(ContainSubstring(`    Selected dotnet-aspnetcore version (using BP_DOTNET_FRAMEWORK_VERSION): 6.0.2 (resolved from "6")`))
+			})
+		})
+	})
+
+	context("ResolutionError", func() {
+		it("explains a NoMatchingVersionError", func() {
+			emitter.ResolutionError(dotnetcoreaspnet.NoMatchingVersionError{
+				ID:                "dotnet-aspnetcore",
+				Version:           "5.0.*",
+				Source:            "BP_DOTNET_FRAMEWORK_VERSION",
+				Stack:             "some-stack",
+				AvailableVersions: []string{"6.0.1", "6.0.2"},
+				Suggestion:        "Set $BP_DOTNET_FRAMEWORK_VERSION to one of the available versions.",
+			})
+
+			Expect(buffer.String()).To(ContainSubstring("    ERROR: No version of dotnet-aspnetcore matches the requested version"))
+			Expect(buffer.String()).To(ContainSubstring(`      Requested version: "5.0.*" (using BP_DOTNET_FRAMEWORK_VERSION)`))
+			Expect(buffer.String()).To(ContainSubstring("      Available versions on some-stack: 6.0.1, 6.0.2"))
+			Expect(buffer.String()).To(ContainSubstring("      Suggested fix: Set $BP_DOTNET_FRAMEWORK_VERSION to one of the available versions."))
+		})
+
+		it("explains an UnsupportedStackError", func() {
+			emitter.ResolutionError(fmt.Errorf("wrapped: %w", dotnetcoreaspnet.UnsupportedStackError{
+				ID:              "dotnet-aspnetcore",
+				Version:         "6.0.*",
+				Source:          "some-app.csproj",
+				Stack:           "some-stack",
+				SupportedStacks: []string{"other-stack"},
+				Suggestion:      "Build the app on one of the supported stacks.",
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("    ERROR: dotnet-aspnetcore is not available on the some-stack stack"))
+			Expect(buffer.String()).To(ContainSubstring(`      Requested version: "6.0.*" (using some-app.csproj)`))
+			Expect(buffer.String()).To(ContainSubstring("      Supported stacks: other-stack"))
+			Expect(buffer.String()).To(ContainSubstring("      Suggested fix: Build the app on one of the supported stacks."))
+		})
+
+		it("explains an UnsupportedArchitectureError", func() {
+			emitter.ResolutionError(dotnetcoreaspnet.UnsupportedArchitectureError{
+				ID:                     "dotnet-aspnetcore",
+				Version:                "6.0.*",
+				Source:                 "BP_DOTNET_FRAMEWORK_VERSION",
+				Stack:                  "some-stack",
+				Arch:                   "arm64",
+				SupportedArchitectures: []string{"amd64"},
+				Suggestion:             "Build the app on a node of one of the supported architectures, or set $BP_DOTNET_ASPNET_ARCH to cross-build for one.",
+			})
+
+			Expect(buffer.String()).To(ContainSubstring("    ERROR: dotnet-aspnetcore is not available for the arm64 architecture on the some-stack stack"))
+			Expect(buffer.String()).To(ContainSubstring(`      Requested version: "6.0.*" (using BP_DOTNET_FRAMEWORK_VERSION)`))
+			Expect(buffer.String()).To(ContainSubstring("      Supported architectures: amd64"))
+			Expect(buffer.String()).To(ContainSubstring("      Suggested fix: Build the app on a node of one of the supported architectures"))
+		})
+
+		it("explains a DeprecatedVersionError", func() {
+			emitter.ResolutionError(dotnetcoreaspnet.DeprecatedVersionError{
+				ID:                "dotnet-aspnetcore",
+				Version:           "5.0.14",
+				Source:            "runtimeconfig.json",
+				Stack:             "some-stack",
+				DeprecationDate:   time.Date(2022, time.May, 8, 0, 0, 0, 0, time.UTC),
+				AvailableVersions: []string{"6.0.2"},
+				Suggestion:        "Publish the app against one of the available versions of Microsoft.AspNetCore.App, or set $BP_DOTNET_FRAMEWORK_VERSION.",
+			})
+
+			Expect(buffer.String()).To(ContainSubstring("    ERROR: Version 5.0.14 of dotnet-aspnetcore was deprecated on 2022-05-08"))
+			Expect(buffer.String()).To(ContainSubstring("      Requested by: runtimeconfig.json"))
+			Expect(buffer.String()).To(ContainSubstring("      Supported versions on some-stack: 6.0.2"))
+			Expect(buffer.String()).To(ContainSubstring("      Suggested fix: Publish the app against one of the available versions of Microsoft.AspNetCore.App"))
+		})
+
+		it("does not log other errors", func() {
+			emitter.ResolutionError(errors.New("some-error"))
+
+			Expect(buffer.String()).To(BeEmpty())
+		})
+	})
+
+	context("Deprecation", func() {
+		var (
+			now        time.Time
+			dependency postal.Dependency
+		)
+
+		it.Before(func() {
+			now = time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)
+			dependency = postal.Dependency{
+				ID:              "dotnet-aspnetcore",
+				Version:         "5.0.14",
+				DeprecationDate: time.Date(2022, time.May, 8, 0, 0, 0, 0, time.UTC),
+			}
+		})
+
+		it("warns when the deprecation date is within the window", func() {
+			emitter.Deprecation(dependency, now, 10*24*time.Hour)
+
+			Expect(buffer.String()).To(ContainSubstring("    WARNING: Version 5.0.14 of dotnet-aspnetcore will be deprecated after 2022-05-08."))
+			Expect(buffer.String()).To(ContainSubstring("    Migrate your application to a supported version of dotnet-aspnetcore before this time."))
+		})
+
+		it("does not warn when the deprecation date is outside the window", func() {
+			emitter.Deprecation(dependency, now, 5*24*time.Hour)
+
+			Expect(buffer.String()).To(BeEmpty())
+		})
+
+		it("warns when the deprecation date has passed", func() {
+			emitter.Deprecation(dependency, now.Add(30*24*time.Hour), 0)
+
+			Expect(buffer.String()).To(ContainSubstring("    WARNING: Version 5.0.14 of dotnet-aspnetcore was deprecated on 2022-05-08."))
+			Expect(buffer.String()).To(ContainSubstring("    Migrate your application to a supported version of dotnet-aspnetcore."))
+		})
+
+		it("does not warn when there is no deprecation date", func() {
+			emitter.Deprecation(postal.Dependency{ID: "dotnet-aspnetcore"}, now, 10*24*time.Hour)
+
+			Expect(buffer.String()).To(BeEmpty())
+		})
+	})
+
+	context("DownloadProgress", func() {
+		it("logs the progress out of the total size", func() {
+			emitter.DownloadProgress(3*1024*1024, 12*1024*1024, 2*time.Second)
+
+			Expect(buffer.String()).To(ContainSubstring("      Downloaded 3.0 MiB of 12.0 MiB (25%) at 1.5 MiB/s"))
+		})
+
+		it("logs the progress when the total size is unknown", func() {
+			emitter.DownloadProgress(512, -1, time.Second)
+
+			Expect(buffer.String()).To(ContainSubstring("      Downloaded 512 B at 512 B/s"))
+		})
+	})
+
+	context("DownloadRetry", func() {
+		it("logs the failure and the delay before the retry", func() {
+			emitter.DownloadRetry(2, 5, 4*time.Second, errors.New("unexpected EOF"))
+
+			Expect(buffer.String()).To(ContainSubstring("      Download failed (attempt 2 of 5): unexpected EOF"))
+			Expect(buffer.String()).To(ContainSubstring("      Retrying in 4s"))
+		})
+	})
 }
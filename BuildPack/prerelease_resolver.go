@@ -0,0 +1,55 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver"
+)
+
+type PrereleaseResolver struct{}
+
+func NewPrereleaseResolver() PrereleaseResolver {
+	return PrereleaseResolver{}
+}
+
+// Resolve returns the highest version of the dependency with the given id
+// that satisfies the given constraint. Unlike the usual constraint
+// resolution, pre-releases are candidates too: a pre-release such as
+// 7.0.0-rc.2.22476.2 satisfies every constraint that its release, 7.0.0,
+// would satisfy. A release still outranks its own pre-releases. The
+// constraint is normalized like DependencyService does, so "default" selects
+// the default version of the dependency.
+func (r PrereleaseResolver) Resolve(path, id, constraint, stack, arch string) (string, error) {
+	metadata, err := parseBuildpackMetadata(path)
+	if err != nil {
+		return "", err
+	}
+
+	constraint = metadata.versionConstraint(id, constraint)
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", invalidVersionError{fmt.Errorf("failed to parse version constraint %q: %w", constraint, err)}
+	}
+
+	found, err := findCandidates(metadata.Dependencies, id, stack, arch)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []*semver.Version
+	for _, candidate := range found.candidates {
+		if c.Check(candidate.version) || c.Check(releaseOf(candidate.version)) {
+			candidates = append(candidates, candidate.version)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", found.noneMatch(fmt.Sprintf("failed to satisfy %q dependency version constraint %q: no compatible versions", id, constraint))
+	}
+
+	sort.Sort(semver.Collection(candidates))
+
+	return candidates[len(candidates)-1].Original(), nil
+}
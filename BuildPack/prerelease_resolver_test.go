@@ -0,0 +1,147 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testPrereleaseResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir   string
+		path     string
+		resolver dotnetcoreaspnet.PrereleaseResolver
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = ioutil.TempDir("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(cnbDir, "buildpack.toml")
+		Expect(ioutil.WriteFile(path, []byte(`
+[metadata.default-versions]
+  dotnet-aspnetcore = "7.0.*"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.0.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "7.0.0-rc.1.22427.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "7.0.0-rc.2.22476.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["other-stack"]
+  version = "7.0.0-rc.3"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["*"]
+  version = "9.1.0-preview.1"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "8.0.0-preview.1"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "8.0.0"
+`), 0600)).To(Succeed())
+
+		resolver = dotnetcoreaspnet.NewPrereleaseResolver()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+	})
+
+	context("Resolve", func() {
+		it("selects the highest pre-release that satisfies a wildcard constraint", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "7.0.*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("7.0.0-rc.2.22476.2"))
+		})
+
+		it("selects a release over its pre-releases", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "8.0.*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("8.0.0"))
+		})
+
+		it("selects an exact pre-release", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "7.0.0-rc.1.22427.2", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("7.0.0-rc.1.22427.2"))
+		})
+
+		it("selects releases as usual", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("6.0.2"))
+		})
+
+		it("selects pre-releases built for any stack", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "9.1.*", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("9.1.0-preview.1"))
+		})
+
+		it("selects the default version for the default constraint", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "default", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("7.0.0-rc.2.22476.2"))
+		})
+
+		it("supports the pessimistic operator", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "~> 7.0", "some-stack", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("7.0.0-rc.2.22476.2"))
+		})
+
+		context("failure cases", func() {
+			context("when no version satisfies the constraint", func() {
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "9.0.*", "some-stack", "amd64")
+					Expect(err).To(MatchError(`failed to satisfy "dotnet-aspnetcore" dependency version constraint "9.0.*": no compatible versions on "some-stack" stack for the amd64 architecture. Supported versions are: [6.0.2, 7.0.0-rc.1.22427.2, 7.0.0-rc.2.22476.2, 9.1.0-preview.1, 8.0.0-preview.1, 8.0.0]`))
+				})
+			})
+
+			context("when the constraint is malformed", func() {
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "not-a-constraint", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse version constraint "not-a-constraint"`)))
+				})
+			})
+
+			context("when the buildpack.toml cannot be parsed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "7.0.*", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,348 @@
+package dotnetcoreaspnet
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+var (
+	targetFrameworkRe = regexp.MustCompile(`^net(?:coreapp)?(\d+)\.(\d+)(?:-.+)?$`)
+	solutionProjectRe = regexp.MustCompile(`^Project\("\{[0-9A-Fa-f-]+\}"\)\s*=\s*"[^"]*"\s*,\s*"([^"]+\.(?:cs|fs|vb)proj)"`)
+)
+
+type ProjectFileParser struct{}
+
+func NewProjectFileParser() ProjectFileParser {
+	return ProjectFileParser{}
+}
+
+// FindProjectFile returns the path to the first C#, F# or VB project file in
+// the given directory. If there is no project file, but there is a solution
+// file, the web project (Sdk="Microsoft.NET.Sdk.Web") of the solution is
+// returned instead; projects of the solution that are missing from the app
+// source are skipped, and it is an error for the web projects of a solution
+// to target different frameworks. Their target frameworks are inherited from
+// Directory.Build.props up to root, the root of the app, as they are by
+// ParseTargetFrameworks. If neither is found, an empty path is returned.
+func (p ProjectFileParser) FindProjectFile(dir, root string) (string, error) {
+	for _, pattern := range []string{"*.csproj", "*.fsproj", "*.vbproj"} {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return "", err
+		}
+
+		if len(files) > 0 {
+			return files[0], nil
+		}
+	}
+
+	solutions, err := filepath.Glob(filepath.Join(dir, "*.sln"))
+	if err != nil {
+		return "", err
+	}
+
+	if len(solutions) == 0 {
+		return "", nil
+	}
+
+	projects, err := parseSolution(solutions[0])
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		webProjects []string
+		frameworks  = map[string]string{}
+		versions    = map[string]bool{}
+	)
+
+	for _, project := range projects {
+		// projects of the solution, such as test projects, are often left out
+		// of the app source, so only the ones that are present are judged
+		web, err := p.IsWebProject(project)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return "", err
+		}
+
+		if !web {
+			continue
+		}
+
+		targets, err := p.ParseTargetFrameworks(project, root)
+		if err != nil {
+			return "", err
+		}
+
+		framework, err := selectTargetFramework(targets, "")
+		if err != nil {
+			return "", err
+		}
+
+		webProjects = append(webProjects, project)
+		frameworks[project] = framework
+		if framework != "" {
+			versions[TargetFrameworkVersion(framework)] = true
+		}
+	}
+
+	if len(versions) > 1 {
+		var conflicts []string
+		for _, project := range webProjects {
+			rel, _ := filepath.Rel(root, project)
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", rel, frameworks[project]))
+		}
+
+		return "", fmt.Errorf("web projects in %s target conflicting frameworks: [%s]: set $BP_DOTNET_PROJECT_PATH to the directory of the project to build", filepath.Base(solutions[0]), strings.Join(conflicts, ", "))
+	}
+
+	if len(webProjects) == 0 {
+		return "", nil
+	}
+
+	return webProjects[0], nil
+}
+
+// ParseTargetFrameworks returns the target frameworks of the given project
+// file. Like MSBuild, properties that the project file does not declare are
+// inherited from the nearest Directory.Build.props, and from any
+// Directory.Build.props further up that it imports, without leaving root.
+// A singular TargetFramework takes precedence over TargetFrameworks.
+func (p ProjectFileParser) ParseTargetFrameworks(path, root string) ([]string, error) {
+	properties, err := parseMSBuildFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	targetFramework, targetFrameworks := properties.TargetFramework, properties.TargetFrameworks
+
+	dir := filepath.Dir(path)
+	for targetFramework == "" && targetFrameworks == "" {
+		props, err := findDirectoryBuildProps(dir, root)
+		if err != nil {
+			return nil, err
+		}
+
+		if props == "" {
+			break
+		}
+
+		properties, err = parseMSBuildFile(props)
+		if err != nil {
+			return nil, err
+		}
+
+		targetFramework, targetFrameworks = properties.TargetFramework, properties.TargetFrameworks
+
+		if !properties.ImportsParentProps {
+			break
+		}
+
+		dir = filepath.Dir(filepath.Dir(props))
+	}
+
+	if targetFramework != "" {
+		return []string{targetFramework}, nil
+	}
+
+	var frameworks []string
+	for _, framework := range strings.Split(targetFrameworks, ";") {
+		if framework = strings.TrimSpace(framework); framework != "" {
+			frameworks = append(frameworks, framework)
+		}
+	}
+
+	return frameworks, nil
+}
+
+// TargetFrameworkVersion returns a version constraint matching the major and
+// minor version of the given target framework moniker (e.g. netcoreapp3.1
+// becomes 3.1.*). If the moniker is not .NET Core or .NET 5+, an empty
+// version is returned.
+func TargetFrameworkVersion(targetFramework string) string {
+	matches := targetFrameworkRe.FindStringSubmatch(targetFramework)
+	if len(matches) != 3 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s.%s.*", matches[1], matches[2])
+}
+
+// selectTargetFramework picks the framework to provide for a project that
+// may target several. The given override must be one of the frameworks;
+// otherwise the highest supported framework is picked.
+func selectTargetFramework(frameworks []string, override string) (string, error) {
+	var supported []string
+	for _, framework := range frameworks {
+		if TargetFrameworkVersion(framework) != "" {
+			supported = append(supported, framework)
+		}
+	}
+
+	if override != "" {
+		for _, framework := range supported {
+			if strings.EqualFold(framework, override) {
+				return framework, nil
+			}
+		}
+
+		return "", fmt.Errorf("$BP_DOTNET_TARGET_FRAMEWORK %q does not match a supported target framework of the project: [%s]", override, strings.Join(supported, ", "))
+	}
+
+	if len(supported) == 0 {
+		return "", nil
+	}
+
+	sort.SliceStable(supported, func(i, j int) bool {
+		left := semver.MustParse(strings.TrimSuffix(TargetFrameworkVersion(supported[i]), ".*"))
+		right := semver.MustParse(strings.TrimSuffix(TargetFrameworkVersion(supported[j]), ".*"))
+		return left.GreaterThan(right)
+	})
+
+	return supported[0], nil
+}
+
+// parseSolution returns the paths of the projects referenced by the given
+// solution file.
+func parseSolution(path string) ([]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, line := range strings.Split(string(content), "\n") {
+		matches := solutionProjectRe.FindStringSubmatch(strings.TrimSpace(line))
+		if len(matches) != 2 {
+			continue
+		}
+
+		// solution files are written on Windows and use its path separator
+		project := strings.ReplaceAll(matches[1], `\`, string(filepath.Separator))
+		projects = append(projects, filepath.Join(filepath.Dir(path), project))
+	}
+
+	return projects, nil
+}
+
+// IsWebProject reports whether the given project file uses the
+// Microsoft.NET.Sdk.Web SDK, either through the Sdk attribute of the Project
+// element or through an Sdk element.
+func (p ProjectFileParser) IsWebProject(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	var project struct {
+		SDK  string `xml:"Sdk,attr"`
+		SDKs []struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Sdk"`
+	}
+
+	err = xml.NewDecoder(file).Decode(&project)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+
+	sdks := strings.Split(project.SDK, ";")
+	for _, sdk := range project.SDKs {
+		sdks = append(sdks, sdk.Name)
+	}
+
+	for _, sdk := range sdks {
+		// the Sdk attribute may pin a version, e.g. Microsoft.NET.Sdk.Web/6.0.100
+		if strings.EqualFold(strings.SplitN(strings.TrimSpace(sdk), "/", 2)[0], "Microsoft.NET.Sdk.Web") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type msbuildProperties struct {
+	TargetFramework    string
+	TargetFrameworks   string
+	ImportsParentProps bool
+}
+
+func parseMSBuildFile(path string) (msbuildProperties, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return msbuildProperties{}, err
+	}
+	defer file.Close()
+
+	var project struct {
+		PropertyGroups []struct {
+			TargetFramework  string `xml:"TargetFramework"`
+			TargetFrameworks string `xml:"TargetFrameworks"`
+		} `xml:"PropertyGroup"`
+		Imports []struct {
+			Project string `xml:"Project,attr"`
+		} `xml:"Import"`
+	}
+
+	err = xml.NewDecoder(file).Decode(&project)
+	if err != nil {
+		return msbuildProperties{}, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+
+	// later property definitions override earlier ones
+	var properties msbuildProperties
+	for _, group := range project.PropertyGroups {
+		if framework := strings.TrimSpace(group.TargetFramework); framework != "" {
+			properties.TargetFramework = framework
+		}
+
+		if frameworks := strings.TrimSpace(group.TargetFrameworks); frameworks != "" {
+			properties.TargetFrameworks = frameworks
+		}
+	}
+
+	for _, imp := range project.Imports {
+		if strings.Contains(imp.Project, "Directory.Build.props") {
+			properties.ImportsParentProps = true
+		}
+	}
+
+	return properties, nil
+}
+
+func findDirectoryBuildProps(dir, root string) (string, error) {
+	for {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", nil
+		}
+
+		path := filepath.Join(dir, "Directory.Build.props")
+		_, err = os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if rel == "." {
+			return "", nil
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,402 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testProjectFileParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		parser     dotnetcoreaspnet.ProjectFileParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = ioutil.TempDir("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		parser = dotnetcoreaspnet.NewProjectFileParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("FindProjectFile", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.fsproj"), nil, 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.csproj"), nil, 0600)).To(Succeed())
+		})
+
+		it("returns the path to the project file", func() {
+			path, err := parser.FindProjectFile(workingDir, workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join(workingDir, "some-app.csproj")))
+		})
+
+		context("when there is no project file", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "some-app.fsproj"))).To(Succeed())
+				Expect(os.Remove(filepath.Join(workingDir, "some-app.csproj"))).To(Succeed())
+			})
+
+			it("returns an empty path", func() {
+				path, err := parser.FindProjectFile(workingDir, workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(path).To(BeEmpty())
+			})
+		})
+
+		context("when there is only a solution file", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "some-app.fsproj"))).To(Succeed())
+				Expect(os.Remove(filepath.Join(workingDir, "some-app.csproj"))).To(Succeed())
+
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.sln"), []byte(`
+Microsoft Visual Studio Solution File, Format Version 12.00
+# Visual Studio Version 17
+Project("{2150E333-8FDC-42A3-9474-1A3956D46DE8}") = "src", "src", "{8E3A3C3B-6A2B-4BB0-8E0F-3F6C1C2D5E3A}"
+EndProject
+Project("{9A19103F-16F7-4668-BE54-9A1E7A4F7556}") = "Api", "src\Api\Api.csproj", "{5B1A2C7A-0C1C-4C3E-9E38-1C1F5C1D7F10}"
+EndProject
+Project("{9A19103F-16F7-4668-BE54-9A1E7A4F7556}") = "Worker", "src\Worker\Worker.csproj", "{6C2B3D8B-1D2D-4D4F-AF49-2D2A6D2E8A21}"
+EndProject
+Global
+EndGlobal
+`), 0600)).To(Succeed())
+
+				Expect(os.MkdirAll(filepath.Join(workingDir, "src", "Api"), os.ModePerm)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Api", "Api.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+
+				Expect(os.MkdirAll(filepath.Join(workingDir, "src", "Worker"), os.ModePerm)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Worker", "Worker.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk.Worker">
+  <PropertyGroup>
+    <TargetFramework>net7.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+			})
+
+			it("returns the path to the web project of the solution", func() {
+				path, err := parser.FindProjectFile(workingDir, workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(path).To(Equal(filepath.Join(workingDir, "src", "Api", "Api.csproj")))
+			})
+
+			context("when the solution has no web project", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Api", "Api.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+				})
+
+				it("returns an empty path", func() {
+					path, err := parser.FindProjectFile(workingDir, workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path).To(BeEmpty())
+				})
+			})
+
+			context("when the web SDK is referenced through an Sdk element", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Worker", "Worker.csproj"), []byte(`<Project>
+  <Sdk Name="Microsoft.NET.Sdk.Web" Version="6.0.100" />
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+				})
+
+				it("treats it as a web project that agrees with the others", func() {
+					path, err := parser.FindProjectFile(workingDir, workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path).To(Equal(filepath.Join(workingDir, "src", "Api", "Api.csproj")))
+				})
+			})
+
+			context("when the web projects target conflicting frameworks", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Worker", "Worker.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk.Web/7.0.100">
+  <PropertyGroup>
+    <TargetFramework>net7.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+				})
+
+				it("returns an error listing the conflicting projects", func() {
+					_, err := parser.FindProjectFile(workingDir, workingDir)
+					Expect(err).To(MatchError("web projects in some-app.sln target conflicting frameworks: [src/Api/Api.csproj (net6.0), src/Worker/Worker.csproj (net7.0)]: set $BP_DOTNET_PROJECT_PATH to the directory of the project to build"))
+				})
+			})
+
+			context("when a project of the solution is missing from the app source", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.sln"), []byte(`
+Microsoft Visual Studio Solution File, Format Version 12.00
+# Visual Studio Version 17
+Project("{9A19103F-16F7-4668-BE54-9A1E7A4F7556}") = "Api.Tests", "test\Api.Tests\Api.Tests.csproj", "{7D3C4E9C-2E3E-4E5A-B05A-3E3B7E3F9B32}"
+EndProject
+Project("{9A19103F-16F7-4668-BE54-9A1E7A4F7556}") = "Api", "src\Api\Api.csproj", "{5B1A2C7A-0C1C-4C3E-9E38-1C1F5C1D7F10}"
+EndProject
+Project("{9A19103F-16F7-4668-BE54-9A1E7A4F7556}") = "Worker", "src\Worker\Worker.csproj", "{6C2B3D8B-1D2D-4D4F-AF49-2D2A6D2E8A21}"
+EndProject
+Global
+EndGlobal
+`), 0600)).To(Succeed())
+				})
+
+				it("skips it and returns the web project that is present", func() {
+					path, err := parser.FindProjectFile(workingDir, workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(path).To(Equal(filepath.Join(workingDir, "src", "Api", "Api.csproj")))
+				})
+			})
+
+			context("when the solution is in a subdirectory of the app", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(workingDir, "app"), os.ModePerm)).To(Succeed())
+					Expect(os.Rename(filepath.Join(workingDir, "some-app.sln"), filepath.Join(workingDir, "app", "some-app.sln"))).To(Succeed())
+					Expect(os.Rename(filepath.Join(workingDir, "src"), filepath.Join(workingDir, "app", "src"))).To(Succeed())
+
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "app", "src", "Worker", "Worker.csproj"), []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+</Project>`), 0600)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "Directory.Build.props"), []byte(`<Project>
+  <PropertyGroup>
+    <TargetFramework>net7.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+				})
+
+				it("judges the web projects by the frameworks they inherit from the root of the app", func() {
+					_, err := parser.FindProjectFile(filepath.Join(workingDir, "app"), workingDir)
+					Expect(err).To(MatchError("web projects in some-app.sln target conflicting frameworks: [app/src/Api/Api.csproj (net6.0), app/src/Worker/Worker.csproj (net7.0)]: set $BP_DOTNET_PROJECT_PATH to the directory of the project to build"))
+				})
+			})
+
+			context("when a project of the solution is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Worker", "Worker.csproj"), []byte(`<Project`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.FindProjectFile(workingDir, workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode Worker.csproj")))
+				})
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the glob is malformed", func() {
+				it("returns an error", func() {
+					_, err := parser.FindProjectFile(`\`, `\`)
+					Expect(err).To(MatchError(ContainSubstring("syntax error in pattern")))
+				})
+			})
+		})
+	})
+
+	context("ParseTargetFrameworks", func() {
+		var path string
+
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "src", "some-app"), os.ModePerm)).To(Succeed())
+
+			path = filepath.Join(workingDir, "src", "some-app", "some-app.csproj")
+			Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+		})
+
+		it("returns the TargetFramework", func() {
+			frameworks, err := parser.ParseTargetFrameworks(path, workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(frameworks).To(Equal([]string{"net6.0"}))
+		})
+
+		context("when the project declares TargetFrameworks", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <OutputType>Exe</OutputType>
+  </PropertyGroup>
+  <PropertyGroup>
+    <TargetFrameworks> net6.0;netcoreapp3.1; </TargetFrameworks>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+			})
+
+			it("returns each of the TargetFrameworks", func() {
+				frameworks, err := parser.ParseTargetFrameworks(path, workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(frameworks).To(Equal([]string{"net6.0", "netcoreapp3.1"}))
+			})
+		})
+
+		context("when the TargetFramework is inherited from Directory.Build.props", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+</Project>`), 0600)).To(Succeed())
+
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Directory.Build.props"), []byte(`<Project>
+  <PropertyGroup>
+    <Nullable>enable</Nullable>
+  </PropertyGroup>
+  <Import Project="$([MSBuild]::GetPathOfFileAbove('Directory.Build.props', '$(MSBuildThisFileDirectory)../'))" />
+</Project>`), 0600)).To(Succeed())
+
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "Directory.Build.props"), []byte(`<Project>
+  <PropertyGroup>
+    <TargetFrameworks>net6.0;net7.0</TargetFrameworks>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+			})
+
+			it("follows the imports up the directory tree", func() {
+				frameworks, err := parser.ParseTargetFrameworks(path, workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(frameworks).To(Equal([]string{"net6.0", "net7.0"}))
+			})
+
+			context("when the nearest Directory.Build.props does not import its parent", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "src", "Directory.Build.props"), []byte(`<Project>
+  <PropertyGroup>
+    <Nullable>enable</Nullable>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+				})
+
+				it("stops at the nearest Directory.Build.props", func() {
+					frameworks, err := parser.ParseTargetFrameworks(path, workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(frameworks).To(BeEmpty())
+				})
+			})
+
+			context("when the Directory.Build.props is outside of the root", func() {
+				it("does not inherit from it", func() {
+					frameworks, err := parser.ParseTargetFrameworks(path, filepath.Join(workingDir, "src", "some-app"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(frameworks).To(BeEmpty())
+				})
+			})
+
+			context("when the project file declares its own TargetFramework", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>netcoreapp3.1</TargetFramework>
+  </PropertyGroup>
+</Project>`), 0600)).To(Succeed())
+				})
+
+				it("overrides the inherited TargetFrameworks", func() {
+					frameworks, err := parser.ParseTargetFrameworks(path, workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(frameworks).To(Equal([]string{"netcoreapp3.1"}))
+				})
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the project file does not exist", func() {
+				it.Before(func() {
+					Expect(os.Remove(path)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseTargetFrameworks(path, workingDir)
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+
+			context("when the project file is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte(`<Project`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseTargetFrameworks(path, workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode some-app.csproj")))
+				})
+			})
+
+			context("when the Directory.Build.props is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk.Web"></Project>`), 0600)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "Directory.Build.props"), []byte(`<Project`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.ParseTargetFrameworks(path, workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode Directory.Build.props")))
+				})
+			})
+		})
+	})
+
+	context("IsWebProject", func() {
+		var path string
+
+		it.Before(func() {
+			path = filepath.Join(workingDir, "some-app.csproj")
+		})
+
+		it("reports whether the project uses the web SDK", func() {
+			Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk.Web"></Project>`), 0600)).To(Succeed())
+
+			web, err := parser.IsWebProject(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(web).To(BeTrue())
+
+			Expect(ioutil.WriteFile(path, []byte(`<Project Sdk="Microsoft.NET.Sdk"></Project>`), 0600)).To(Succeed())
+
+			web, err = parser.IsWebProject(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(web).To(BeFalse())
+		})
+
+		context("failure cases", func() {
+			context("when the project file is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte(`<Project`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.IsWebProject(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode some-app.csproj")))
+				})
+			})
+		})
+	})
+
+	context("TargetFrameworkVersion", func() {
+		it("returns a constraint for the major and minor of the target framework", func() {
+			Expect(dotnetcoreaspnet.TargetFrameworkVersion("net6.0")).To(Equal("6.0.*"))
+			Expect(dotnetcoreaspnet.TargetFrameworkVersion("net6.0-windows")).To(Equal("6.0.*"))
+			Expect(dotnetcoreaspnet.TargetFrameworkVersion("netcoreapp3.1")).To(Equal("3.1.*"))
+		})
+
+		it("returns an empty version for frameworks other than .NET Core or .NET 5+", func() {
+			Expect(dotnetcoreaspnet.TargetFrameworkVersion("netstandard2.0")).To(BeEmpty())
+			Expect(dotnetcoreaspnet.TargetFrameworkVersion("net48")).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,142 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/paketo-buildpacks/packit"
+)
+
+type RollForwardResolver struct{}
+
+func NewRollForwardResolver() RollForwardResolver {
+	return RollForwardResolver{}
+}
+
+// Resolve returns the version of the dependency with the given id that the
+// .NET host would load for a framework reference of the given version under
+// the given roll-forward policy, choosing from the dependencies listed in
+// the buildpack.toml at path. Policies are matched case-insensitively, as
+// they are by the host.
+//
+// See https://docs.microsoft.com/en-us/dotnet/core/versions/selection#framework-dependent-apps-roll-forward
+func (r RollForwardResolver) Resolve(path, id, version, policy, stack, arch string) (string, error) {
+	requested, err := semver.NewVersion(version)
+	if err != nil {
+		return "", invalidVersionError{fmt.Errorf("failed to parse requested version %q: %w", version, err)}
+	}
+
+	dependencies, err := parseDependencies(path)
+	if err != nil {
+		return "", err
+	}
+
+	found, err := findCandidates(dependencies, id, stack, arch)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []*semver.Version
+	for _, candidate := range found.candidates {
+		// the host never rolls backwards, and only rolls forward onto a
+		// pre-release when a pre-release was requested
+		v := candidate.version
+		if v.LessThan(requested) || (v.Prerelease() != "" && requested.Prerelease() == "") {
+			continue
+		}
+
+		candidates = append(candidates, v)
+	}
+
+	sort.Sort(semver.Collection(candidates))
+
+	sameMinor := func(major, minor int64) func(*semver.Version) bool {
+		return func(v *semver.Version) bool {
+			return v.Major() == major && v.Minor() == minor
+		}
+	}
+
+	sameMajor := func(major int64) func(*semver.Version) bool {
+		return func(v *semver.Version) bool {
+			return v.Major() == major
+		}
+	}
+
+	anyVersion := func(*semver.Version) bool { return true }
+
+	lowest := func(match func(*semver.Version) bool) *semver.Version {
+		for _, v := range candidates {
+			if match(v) {
+				return v
+			}
+		}
+		return nil
+	}
+
+	highest := func(match func(*semver.Version) bool) *semver.Version {
+		for i := len(candidates) - 1; i >= 0; i-- {
+			if match(candidates[i]) {
+				return candidates[i]
+			}
+		}
+		return nil
+	}
+
+	// once a major and minor has been chosen, the host always picks the
+	// latest patch that is available for it
+	latestPatchOf := func(v *semver.Version) *semver.Version {
+		if v == nil {
+			return nil
+		}
+		return highest(sameMinor(v.Major(), v.Minor()))
+	}
+
+	var selected *semver.Version
+	switch strings.ToLower(policy) {
+	case "disable":
+		selected = lowest(func(v *semver.Version) bool { return v.Equal(requested) })
+
+	case "latestpatch":
+		selected = highest(sameMinor(requested.Major(), requested.Minor()))
+
+	case "minor":
+		selected = latestPatchOf(lowest(sameMajor(requested.Major())))
+
+	case "latestminor":
+		selected = latestPatchOf(highest(sameMajor(requested.Major())))
+
+	case "major":
+		selected = latestPatchOf(lowest(anyVersion))
+
+	case "latestmajor":
+		selected = latestPatchOf(highest(anyVersion))
+
+	default:
+		return "", invalidVersionError{fmt.Errorf("unsupported roll-forward policy %q: must be one of LatestPatch, Minor, LatestMinor, Major, LatestMajor or Disable", policy)}
+	}
+
+	if selected == nil {
+		return "", found.noneMatch(fmt.Sprintf("failed to roll forward %q dependency version %q using the %s policy: no compatible versions", id, version, policy))
+	}
+
+	return selected.Original(), nil
+}
+
+// RollForwardPolicy returns the roll-forward policy that applies to the
+// version requested by the given entry. Versions from runtimeconfig.json are
+// framework references, which the host rolls forward according to the app's
+// policy even when it does not set one.
+func RollForwardPolicy(entry packit.BuildpackPlanEntry) string {
+	rollForward, _ := entry.Metadata["roll-forward"].(string)
+	if source, _ := entry.Metadata["version-source"].(string); rollForward == "" && source == "runtimeconfig.json" {
+		rollForward = "Minor"
+		if policy, ok := os.LookupEnv("DOTNET_ROLL_FORWARD"); ok {
+			rollForward = policy
+		}
+	}
+
+	return rollForward
+}
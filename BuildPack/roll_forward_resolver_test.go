@@ -0,0 +1,246 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testRollForwardResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir   string
+		path     string
+		resolver dotnetcoreaspnet.RollForwardResolver
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = ioutil.TempDir("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(cnbDir, "buildpack.toml")
+		Expect(ioutil.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "5.0.13"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.0.1"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.0.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["other-stack"]
+  version = "6.0.9"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.2.0"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.2.1"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.3.0"
+
+[[metadata.dependencies]]
+  arch = "arm64"
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.4.0"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "8.0.0-rc.1"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "8.1.0"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "8.1.3"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "9.0.0"
+
+[[metadata.dependencies]]
+  id = "other-dependency"
+  stacks = ["some-stack"]
+  version = "6.0.7"
+`), 0600)).To(Succeed())
+
+		resolver = dotnetcoreaspnet.NewRollForwardResolver()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+	})
+
+	context("Resolve", func() {
+		context("when the policy is Disable", func() {
+			it("selects the exact version", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.1", "Disable", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.0.1"))
+			})
+
+			it("does not roll forward to another patch", func() {
+				_, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "Disable", "some-stack", "amd64")
+				Expect(err).To(MatchError(ContainSubstring(`failed to roll forward "dotnet-aspnetcore" dependency version "6.0.0" using the Disable policy`)))
+			})
+		})
+
+		context("when the policy is LatestPatch", func() {
+			it("selects the latest patch of the requested minor", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "LatestPatch", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.0.2"))
+			})
+
+			it("does not roll forward to another minor", func() {
+				_, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.1.0", "LatestPatch", "some-stack", "amd64")
+				Expect(err).To(MatchError(ContainSubstring("no compatible versions on \"some-stack\" stack")))
+			})
+		})
+
+		context("when the policy is Minor", func() {
+			it("selects the latest patch of the requested minor when it is available", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "Minor", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.0.2"))
+			})
+
+			it("selects the latest patch of the lowest higher minor otherwise", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.1.0", "Minor", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.2.1"))
+			})
+
+			it("does not roll forward to another major", func() {
+				_, err := resolver.Resolve(path, "dotnet-aspnetcore", "7.0.0", "Minor", "some-stack", "amd64")
+				Expect(err).To(MatchError(ContainSubstring("no compatible versions")))
+			})
+
+			it("matches the policy case-insensitively", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.1.0", "minor", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.2.1"))
+			})
+		})
+
+		context("when the policy is LatestMinor", func() {
+			it("selects the latest minor of the requested major", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "LatestMinor", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.3.0"))
+			})
+		})
+
+		context("when the policy is Major", func() {
+			it("selects the latest patch of the requested minor when it is available", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.2.0", "Major", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.2.1"))
+			})
+
+			it("selects the lowest higher major otherwise, skipping pre-releases", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "7.0.0", "Major", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("8.1.3"))
+			})
+		})
+
+		context("when the policy is LatestMajor", func() {
+			it("selects the latest version", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "5.0.0", "LatestMajor", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("9.0.0"))
+			})
+		})
+
+		context("when the architecture is not amd64", func() {
+			it("only rolls forward onto versions for that architecture", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "Minor", "some-stack", "arm64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("6.4.0"))
+			})
+		})
+
+		context("when a pre-release is requested", func() {
+			it("can select a pre-release", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "8.0.0-rc.1", "LatestPatch", "some-stack", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("8.0.0-rc.1"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the requested version is not a version", func() {
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.*", "Minor", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse requested version "6.0.*"`)))
+				})
+			})
+
+			context("when the policy is not supported", func() {
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "Sideways", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring(`unsupported roll-forward policy "Sideways"`)))
+				})
+			})
+
+			context("when the buildpack.toml cannot be parsed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "Minor", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+
+			context("when a dependency version is invalid", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "not-a-version"
+`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "6.0.0", "Minor", "some-stack", "amd64")
+					Expect(err).To(MatchError(ContainSubstring("Invalid Semantic Version")))
+				})
+			})
+		})
+	})
+}
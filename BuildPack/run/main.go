@@ -5,24 +5,53 @@ import (
 
 	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
 	"github.com/paketo-buildpacks/packit"
-	"github.com/paketo-buildpacks/packit/cargo"
 	"github.com/paketo-buildpacks/packit/chronos"
 	"github.com/paketo-buildpacks/packit/draft"
 	"github.com/paketo-buildpacks/packit/postal"
+	"github.com/paketo-buildpacks/packit/servicebindings"
 )
 
 func main() {
 	buildpackYMLParser := dotnetcoreaspnet.NewBuildpackYMLParser()
+	depsJSONParser := dotnetcoreaspnet.NewDepsJSONParser()
+	globalJSONParser := dotnetcoreaspnet.NewGlobalJSONParser()
+	projectFileParser := dotnetcoreaspnet.NewProjectFileParser()
+	runtimeConfigParser := dotnetcoreaspnet.NewRuntimeConfigParser()
 	logEmitter := dotnetcoreaspnet.NewLogEmitter(os.Stdout)
 	entryResolver := draft.NewPlanner()
-	dependencyManager := postal.NewService(cargo.NewTransport())
+	transport := dotnetcoreaspnet.NewTransport(logEmitter)
+	dependencyService := dotnetcoreaspnet.NewDependencyService(postal.NewService(transport))
+	rollForwardResolver := dotnetcoreaspnet.NewRollForwardResolver()
+	versionAliasResolver := dotnetcoreaspnet.NewVersionAliasResolver()
+	prereleaseResolver := dotnetcoreaspnet.NewPrereleaseResolver()
+	lockfile := dotnetcoreaspnet.NewLockfile()
+	frameworkCompatibilityChecker := dotnetcoreaspnet.NewFrameworkCompatibilityChecker()
+	dependencyMappingResolver := dotnetcoreaspnet.NewDependencyMappingResolver(servicebindings.NewResolver())
+	dependencyMirrorResolver := dotnetcoreaspnet.NewDependencyMirrorResolver(servicebindings.NewResolver())
+	downloadCache := dotnetcoreaspnet.NewDownloadCache(transport, chronos.DefaultClock)
+	layerManifest := dotnetcoreaspnet.NewLayerManifest()
 	dotnetRootLinker := dotnetcoreaspnet.NewDotnetRootLinker()
 
 	packit.Run(
-		dotnetcoreaspnet.Detect(buildpackYMLParser),
+		dotnetcoreaspnet.Detect(
+			buildpackYMLParser,
+			globalJSONParser,
+			projectFileParser,
+			runtimeConfigParser,
+			depsJSONParser,
+		),
 		dotnetcoreaspnet.Build(
 			entryResolver,
-			dependencyManager,
+			dependencyService,
+			rollForwardResolver,
+			versionAliasResolver,
+			prereleaseResolver,
+			lockfile,
+			frameworkCompatibilityChecker,
+			dependencyMappingResolver,
+			dependencyMirrorResolver,
+			downloadCache,
+			layerManifest,
 			dotnetRootLinker,
 			logEmitter,
 			chronos.DefaultClock,
@@ -0,0 +1,89 @@
+package dotnetcoreaspnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type RuntimeConfig struct {
+	Path          string
+	Version       string
+	RollForward   string
+	SelfContained bool
+}
+
+type RuntimeConfigParser struct{}
+
+func NewRuntimeConfigParser() RuntimeConfigParser {
+	return RuntimeConfigParser{}
+}
+
+// Parse finds the *.runtimeconfig.json file matching the given glob and
+// returns the version of the Microsoft.AspNetCore.App framework it
+// references. If there is no runtimeconfig.json file, or the file does not
+// reference the framework, the returned RuntimeConfig has an empty Version.
+// The RollForward policy is taken from the framework reference when it sets
+// one, and from runtimeOptions otherwise. Apps published as self-contained
+// list includedFrameworks instead of framework references.
+func (p RuntimeConfigParser) Parse(glob string) (RuntimeConfig, error) {
+	files, err := filepath.Glob(glob)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	if len(files) == 0 {
+		return RuntimeConfig{}, nil
+	}
+
+	if len(files) > 1 {
+		return RuntimeConfig{}, fmt.Errorf("multiple *.runtimeconfig.json files present: %s", strings.Join(files, ", "))
+	}
+
+	config := RuntimeConfig{Path: files[0]}
+
+	file, err := os.Open(config.Path)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	defer file.Close()
+
+	type framework struct {
+		Name        string `json:"name"`
+		Version     string `json:"version"`
+		RollForward string `json:"rollForward"`
+	}
+
+	var data struct {
+		RuntimeOptions struct {
+			Framework          framework   `json:"framework"`
+			Frameworks         []framework `json:"frameworks"`
+			IncludedFrameworks []framework `json:"includedFrameworks"`
+			RollForward        string      `json:"rollForward"`
+		} `json:"runtimeOptions"`
+	}
+
+	err = json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		return RuntimeConfig{}, fmt.Errorf("failed to decode %s: %w", filepath.Base(config.Path), err)
+	}
+
+	config.SelfContained = len(data.RuntimeOptions.IncludedFrameworks) > 0
+
+	frameworks := append([]framework{data.RuntimeOptions.Framework}, data.RuntimeOptions.Frameworks...)
+	for _, f := range frameworks {
+		if f.Name == "Microsoft.AspNetCore.App" {
+			config.Version = f.Version
+			config.RollForward = f.RollForward
+			break
+		}
+	}
+
+	if config.Version != "" && config.RollForward == "" {
+		config.RollForward = data.RuntimeOptions.RollForward
+	}
+
+	return config, nil
+}
@@ -0,0 +1,222 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testRuntimeConfigParser(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		glob       string
+		parser     dotnetcoreaspnet.RuntimeConfigParser
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = ioutil.TempDir("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		glob = filepath.Join(workingDir, "*.runtimeconfig.json")
+
+		parser = dotnetcoreaspnet.NewRuntimeConfigParser()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("Parse", func() {
+		context("when the framework is referenced through runtimeOptions.framework", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "tfm": "net6.0",
+    "framework": {
+      "name": "Microsoft.AspNetCore.App",
+      "version": "6.0.0"
+    }
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("returns the version of the ASP.NET framework", func() {
+				config, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config).To(Equal(dotnetcoreaspnet.RuntimeConfig{
+					Path:    filepath.Join(workingDir, "some-app.runtimeconfig.json"),
+					Version: "6.0.0",
+				}))
+			})
+		})
+
+		context("when the framework is referenced through runtimeOptions.frameworks", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "tfm": "netcoreapp3.1",
+    "frameworks": [
+      {
+        "name": "Microsoft.NETCore.App",
+        "version": "3.1.0"
+      },
+      {
+        "name": "Microsoft.AspNetCore.App",
+        "version": "3.1.0"
+      }
+    ]
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("returns the version of the ASP.NET framework", func() {
+				config, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config).To(Equal(dotnetcoreaspnet.RuntimeConfig{
+					Path:    filepath.Join(workingDir, "some-app.runtimeconfig.json"),
+					Version: "3.1.0",
+				}))
+			})
+		})
+
+		context("when a roll-forward policy is set", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "rollForward": "LatestMinor",
+    "framework": {
+      "name": "Microsoft.AspNetCore.App",
+      "version": "6.0.0"
+    }
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("returns the roll-forward policy", func() {
+				config, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.RollForward).To(Equal("LatestMinor"))
+			})
+
+			context("when the framework reference overrides the roll-forward policy", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "rollForward": "LatestMinor",
+    "frameworks": [
+      {
+        "name": "Microsoft.AspNetCore.App",
+        "version": "6.0.0",
+        "rollForward": "Disable"
+      }
+    ]
+  }
+}`), 0600)).To(Succeed())
+				})
+
+				it("returns the roll-forward policy of the framework reference", func() {
+					config, err := parser.Parse(glob)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.RollForward).To(Equal("Disable"))
+				})
+			})
+		})
+
+		context("when the ASP.NET framework is not referenced", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "framework": {
+      "name": "Microsoft.NETCore.App",
+      "version": "6.0.0"
+    }
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("returns an empty version", func() {
+				config, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Version).To(BeEmpty())
+			})
+		})
+
+		context("when the app is self-contained", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{
+  "runtimeOptions": {
+    "tfm": "net6.0",
+    "includedFrameworks": [
+      {
+        "name": "Microsoft.NETCore.App",
+        "version": "6.0.2"
+      },
+      {
+        "name": "Microsoft.AspNetCore.App",
+        "version": "6.0.2"
+      }
+    ]
+  }
+}`), 0600)).To(Succeed())
+			})
+
+			it("reports that the app is self-contained", func() {
+				config, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config).To(Equal(dotnetcoreaspnet.RuntimeConfig{
+					Path:          filepath.Join(workingDir, "some-app.runtimeconfig.json"),
+					SelfContained: true,
+				}))
+			})
+		})
+
+		context("when there is no runtimeconfig.json", func() {
+			it("returns an empty config", func() {
+				config, err := parser.Parse(glob)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config).To(Equal(dotnetcoreaspnet.RuntimeConfig{}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the glob is malformed", func() {
+				it("returns an error", func() {
+					_, err := parser.Parse(`\`)
+					Expect(err).To(MatchError(ContainSubstring("syntax error in pattern")))
+				})
+			})
+
+			context("when there are multiple runtimeconfig.json files", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{}`), 0600)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "other-app.runtimeconfig.json"), []byte(`{}`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.Parse(glob)
+					Expect(err).To(MatchError(ContainSubstring("multiple *.runtimeconfig.json files present")))
+				})
+			})
+
+			context("when the runtimeconfig.json is malformed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(filepath.Join(workingDir, "some-app.runtimeconfig.json"), []byte(`{`), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := parser.Parse(glob)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode some-app.runtimeconfig.json")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,340 @@
+package dotnetcoreaspnet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const maxDownloadBackoff = 30 * time.Second
+
+var contentRangeRe = regexp.MustCompile(`^bytes (\d+)-\d+/(\d+|\*)$`)
+
+// Transport fetches dependencies for a postal.Service. Like cargo.Transport,
+// file:// URIs are relative to the buildpack, where offline packages keep
+// their dependencies; but a file:// URI that is not found in the buildpack
+// is an absolute path, such as a dependency mapped to a file in a service
+// binding.
+//
+// Downloads are retried with exponential backoff when a request fails or
+// the connection drops, resuming from where they broke off with an HTTP
+// Range request, and their progress is logged periodically. A connection
+// that stalls, whether while connecting, waiting for the response or in the
+// middle of the body, times out and is retried like one that drops.
+type Transport struct {
+	client           *http.Client
+	logger           LogEmitter
+	attempts         int
+	backoff          time.Duration
+	readTimeout      time.Duration
+	progressInterval time.Duration
+}
+
+func NewTransport(logger LogEmitter) Transport {
+	return Transport{
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   30 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				ForceAttemptHTTP2:     true,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ResponseHeaderTimeout: 30 * time.Second,
+				IdleConnTimeout:       90 * time.Second,
+			},
+		},
+		logger:           logger,
+		attempts:         5,
+		backoff:          time.Second,
+		readTimeout:      time.Minute,
+		progressInterval: 5 * time.Second,
+	}
+}
+
+// WithRetries makes the transport try a download up to the given number of
+// times in a row without receiving any data, waiting the given backoff
+// before the first retry and twice as long before each next one.
+func (t Transport) WithRetries(attempts int, backoff time.Duration) Transport {
+	t.attempts = attempts
+	t.backoff = backoff
+	return t
+}
+
+// WithReadTimeout sets how long a download may go without receiving any
+// data before its connection is dropped and the download is retried.
+func (t Transport) WithReadTimeout(timeout time.Duration) Transport {
+	t.readTimeout = timeout
+	return t
+}
+
+// WithProgressInterval sets how often the progress of a download is logged.
+func (t Transport) WithProgressInterval(interval time.Duration) Transport {
+	t.progressInterval = interval
+	return t
+}
+
+func (t Transport) Drop(root, uri string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(uri, "file://") {
+		d := &download{
+			transport: t,
+			uri:       uri,
+			total:     -1,
+			start:     time.Now(),
+		}
+		d.lastProgress = d.start
+
+		err := d.connect()
+		if err != nil {
+			return nil, err
+		}
+
+		return d, nil
+	}
+
+	path := strings.TrimPrefix(uri, "file://")
+
+	file, err := os.Open(filepath.Join(root, path))
+	if os.IsNotExist(err) && filepath.IsAbs(path) {
+		file, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %s", err)
+	}
+
+	return file, nil
+}
+
+// download is the body of a dependency download, which reconnects when the
+// connection drops before the body is complete.
+type download struct {
+	transport Transport
+	uri       string
+	body      io.ReadCloser
+
+	received int64
+	total    int64
+	failures int
+
+	start        time.Time
+	lastProgress time.Time
+
+	// idle cancels the request when no data has been received for the read
+	// timeout of the transport, and stalled records that it did
+	idle    *time.Timer
+	cancel  context.CancelFunc
+	stalled int32
+}
+
+func (d *download) Read(p []byte) (int, error) {
+	for {
+		if d.body == nil {
+			err := d.connect()
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := d.body.Read(p)
+		d.received += int64(n)
+
+		if n > 0 {
+			d.idle.Reset(d.transport.readTimeout)
+			d.failures = 0
+			d.progress(false)
+		}
+
+		if err == io.EOF {
+			if d.total < 0 || d.received >= d.total {
+				d.progress(true)
+				return n, io.EOF
+			}
+
+			err = io.ErrUnexpectedEOF
+		}
+
+		if err == nil {
+			return n, nil
+		}
+
+		err = d.stallError(err)
+		d.disconnect()
+
+		retryErr := d.retry(err)
+		if retryErr != nil {
+			return n, retryErr
+		}
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (d *download) Close() error {
+	if d.body == nil {
+		return nil
+	}
+
+	d.idle.Stop()
+	d.cancel()
+
+	err := d.body.Close()
+	d.body = nil
+
+	return err
+}
+
+// disconnect drops the connection of the download.
+func (d *download) disconnect() {
+	d.idle.Stop()
+	d.cancel()
+
+	if d.body != nil {
+		d.body.Close()
+		d.body = nil
+	}
+}
+
+// stallError returns an error that says how long the download stalled for
+// in place of the given error, if the request was canceled because it did.
+func (d *download) stallError(err error) error {
+	if atomic.LoadInt32(&d.stalled) == 1 {
+		return fmt.Errorf("no data received for %s", d.transport.readTimeout)
+	}
+
+	return err
+}
+
+// connect requests the rest of the download, retrying until it succeeds or
+// runs out of attempts.
+func (d *download) connect() error {
+	for {
+		retryable, err := d.request()
+		if err == nil {
+			return nil
+		}
+
+		if !retryable {
+			return err
+		}
+
+		err = d.retry(err)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// request requests the download from the byte it broke off at, if any, and
+// reports whether a failed request is worth retrying.
+func (d *download) request() (bool, error) {
+	request, err := http.NewRequest("GET", d.uri, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse request uri: %s", err)
+	}
+
+	if d.received > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.received))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	atomic.StoreInt32(&d.stalled, 0)
+	d.cancel = cancel
+	d.idle = time.AfterFunc(d.transport.readTimeout, func() {
+		atomic.StoreInt32(&d.stalled, 1)
+		cancel()
+	})
+
+	response, err := d.transport.client.Do(request.WithContext(ctx))
+	if err != nil {
+		err = d.stallError(err)
+		d.disconnect()
+		return true, fmt.Errorf("failed to make request: %s", err)
+	}
+
+	switch {
+	case response.StatusCode == http.StatusPartialContent && d.received > 0:
+		matches := contentRangeRe.FindStringSubmatch(response.Header.Get("Content-Range"))
+		if len(matches) != 3 || matches[1] != strconv.FormatInt(d.received, 10) {
+			response.Body.Close()
+			d.disconnect()
+			return false, fmt.Errorf("failed to resume download: unexpected Content-Range %q", response.Header.Get("Content-Range"))
+		}
+
+		if d.total < 0 && matches[2] != "*" {
+			d.total, _ = strconv.ParseInt(matches[2], 10, 64)
+		}
+
+	case response.StatusCode == http.StatusOK:
+		// the server does not support Range requests, so the part that was
+		// already received is skipped
+		if d.received > 0 {
+			_, err = io.CopyN(ioutil.Discard, response.Body, d.received)
+			if err != nil {
+				err = d.stallError(err)
+				response.Body.Close()
+				d.disconnect()
+				return true, fmt.Errorf("failed to resume download: %s", err)
+			}
+		}
+
+		if d.total < 0 {
+			d.total = response.ContentLength
+		}
+
+	case response.StatusCode >= 500 || response.StatusCode == http.StatusRequestTimeout || response.StatusCode == http.StatusTooManyRequests:
+		response.Body.Close()
+		d.disconnect()
+		return true, fmt.Errorf("unexpected response status: %s", response.Status)
+
+	default:
+		response.Body.Close()
+		d.disconnect()
+		return false, fmt.Errorf("failed to download %s: unexpected response status: %s", RedactCredentials(d.uri), response.Status)
+	}
+
+	d.body = response.Body
+
+	return false, nil
+}
+
+// retry waits before the download is retried after the given error, or
+// returns an error if the download has run out of attempts.
+func (d *download) retry(err error) error {
+	d.failures++
+	if d.failures >= d.transport.attempts {
+		return fmt.Errorf("failed to download %s after %d attempts: %w", RedactCredentials(d.uri), d.failures, err)
+	}
+
+	delay := d.transport.backoff << (d.failures - 1)
+	if delay > maxDownloadBackoff || delay <= 0 {
+		delay = maxDownloadBackoff
+	}
+
+	d.transport.logger.DownloadRetry(d.failures, d.transport.attempts, delay, err)
+	time.Sleep(delay)
+
+	return nil
+}
+
+func (d *download) progress(done bool) {
+	now := time.Now()
+	if !done && now.Sub(d.lastProgress) < d.transport.progressInterval {
+		return
+	}
+
+	d.lastProgress = now
+	d.transport.logger.DownloadProgress(d.received, d.total, now.Sub(d.start))
+}
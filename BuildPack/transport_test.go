@@ -0,0 +1,285 @@
+package dotnetcoreaspnet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTransport(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir    string
+		otherDir  string
+		buffer    *bytes.Buffer
+		transport dotnetcoreaspnet.Transport
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = ioutil.TempDir("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		otherDir, err = ioutil.TempDir("", "other")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(cnbDir, "dependencies"), os.ModePerm)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(cnbDir, "dependencies", "some-file"), []byte("offline-contents"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(otherDir, "some-file"), []byte("substitute-contents"), 0600)).To(Succeed())
+
+		buffer = bytes.NewBuffer(nil)
+		transport = dotnetcoreaspnet.NewTransport(dotnetcoreaspnet.NewLogEmitter(buffer)).WithRetries(3, time.Millisecond)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+		Expect(os.RemoveAll(otherDir)).To(Succeed())
+	})
+
+	context("Drop", func() {
+		context("when the uri is a file uri", func() {
+			it("opens it relative to the buildpack", func() {
+				bundle, err := transport.Drop(cnbDir, "file:///dependencies/some-file")
+				Expect(err).NotTo(HaveOccurred())
+				defer bundle.Close()
+
+				contents, err := ioutil.ReadAll(bundle)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("offline-contents"))
+			})
+
+			it("opens it as an absolute path when it is not in the buildpack", func() {
+				bundle, err := transport.Drop(cnbDir, "file://"+filepath.Join(otherDir, "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				defer bundle.Close()
+
+				contents, err := ioutil.ReadAll(bundle)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("substitute-contents"))
+			})
+
+			context("when the file does not exist", func() {
+				it("returns an error", func() {
+					_, err := transport.Drop(cnbDir, "file:///no/such/file")
+					Expect(err).To(MatchError(ContainSubstring("failed to open file")))
+				})
+			})
+		})
+
+		context("when the uri is an http uri", func() {
+			var (
+				server   *httptest.Server
+				content  string
+				requests []*http.Request
+				handlers []http.HandlerFunc
+			)
+
+			it.Before(func() {
+				content = strings.Repeat("some-content", 1000)
+				requests = nil
+				handlers = nil
+
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					requests = append(requests, req)
+					if len(requests) <= len(handlers) {
+						handlers[len(requests)-1](w, req)
+						return
+					}
+
+					http.ServeContent(w, req, "some-dependency.tar.xz", time.Time{}, strings.NewReader(content))
+				}))
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("downloads the dependency and logs its progress", func() {
+				bundle, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+				Expect(err).NotTo(HaveOccurred())
+				defer bundle.Close()
+
+				contents, err := ioutil.ReadAll(bundle)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal(content))
+
+				Expect(requests).To(HaveLen(1))
+				Expect(buffer.String()).To(MatchRegexp(`Downloaded 11\.7 KiB of 11\.7 KiB \(100%\) at .*/s`))
+			})
+
+			context("when the progress interval has passed", func() {
+				it.Before(func() {
+					transport = transport.WithProgressInterval(0)
+				})
+
+				it("logs the progress periodically", func() {
+					bundle, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+					Expect(err).NotTo(HaveOccurred())
+					defer bundle.Close()
+
+					_, err = ioutil.ReadAll(bundle)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(strings.Count(buffer.String(), "Downloaded ")).To(BeNumerically(">", 1))
+				})
+			})
+
+			context("when a request fails", func() {
+				it.Before(func() {
+					handlers = []http.HandlerFunc{
+						func(w http.ResponseWriter, req *http.Request) {
+							w.WriteHeader(http.StatusServiceUnavailable)
+						},
+					}
+				})
+
+				it("retries it", func() {
+					bundle, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+					Expect(err).NotTo(HaveOccurred())
+					defer bundle.Close()
+
+					contents, err := ioutil.ReadAll(bundle)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal(content))
+
+					Expect(requests).To(HaveLen(2))
+					Expect(buffer.String()).To(ContainSubstring("Download failed (attempt 1 of 3): unexpected response status: 503 Service Unavailable"))
+					Expect(buffer.String()).To(ContainSubstring("Retrying in 1ms"))
+				})
+			})
+
+			context("when the connection drops", func() {
+				it.Before(func() {
+					handlers = []http.HandlerFunc{
+						func(w http.ResponseWriter, req *http.Request) {
+							w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+							w.WriteHeader(http.StatusOK)
+							fmt.Fprint(w, content[:5000])
+						},
+					}
+				})
+
+				it("resumes the download with a Range request", func() {
+					bundle, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+					Expect(err).NotTo(HaveOccurred())
+					defer bundle.Close()
+
+					contents, err := ioutil.ReadAll(bundle)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal(content))
+
+					Expect(requests).To(HaveLen(2))
+					Expect(requests[1].Header.Get("Range")).To(Equal("bytes=5000-"))
+					Expect(buffer.String()).To(ContainSubstring("Download failed (attempt 1 of 3): unexpected EOF"))
+				})
+
+				context("when the connection stalls", func() {
+					it.Before(func() {
+						transport = transport.WithReadTimeout(50 * time.Millisecond)
+
+						handlers = []http.HandlerFunc{
+							func(w http.ResponseWriter, req *http.Request) {
+								w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+								w.WriteHeader(http.StatusOK)
+								fmt.Fprint(w, content[:5000])
+								w.(http.Flusher).Flush()
+
+								select {
+								case <-req.Context().Done():
+								case <-time.After(5 * time.Second):
+								}
+							},
+						}
+					})
+
+					it("times out and resumes the download", func() {
+						bundle, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+						Expect(err).NotTo(HaveOccurred())
+						defer bundle.Close()
+
+						contents, err := ioutil.ReadAll(bundle)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal(content))
+
+						Expect(requests).To(HaveLen(2))
+						Expect(requests[1].Header.Get("Range")).To(Equal("bytes=5000-"))
+						Expect(buffer.String()).To(ContainSubstring("Download failed (attempt 1 of 3): no data received for 50ms"))
+					})
+				})
+
+				context("when the server does not support Range requests", func() {
+					it.Before(func() {
+						handlers = append(handlers, func(w http.ResponseWriter, req *http.Request) {
+							fmt.Fprint(w, content)
+						})
+					})
+
+					it("skips the part that was already received", func() {
+						bundle, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+						Expect(err).NotTo(HaveOccurred())
+						defer bundle.Close()
+
+						contents, err := ioutil.ReadAll(bundle)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal(content))
+					})
+				})
+			})
+
+			context("failure cases", func() {
+				context("when the download keeps failing", func() {
+					it.Before(func() {
+						handlers = []http.HandlerFunc{
+							func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+							func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+							func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+						}
+					})
+
+					it("returns an error after the last attempt", func() {
+						_, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+						Expect(err).To(MatchError(fmt.Sprintf("failed to download %s/some-dependency.tar.xz after 3 attempts: unexpected response status: 502 Bad Gateway", server.URL)))
+
+						Expect(requests).To(HaveLen(3))
+					})
+				})
+
+				context("when the dependency does not exist", func() {
+					it.Before(func() {
+						handlers = []http.HandlerFunc{
+							func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusNotFound) },
+						}
+					})
+
+					it("returns an error without retrying", func() {
+						_, err := transport.Drop(cnbDir, server.URL+"/some-dependency.tar.xz")
+						Expect(err).To(MatchError(fmt.Sprintf("failed to download %s/some-dependency.tar.xz: unexpected response status: 404 Not Found", server.URL)))
+
+						Expect(requests).To(HaveLen(1))
+					})
+				})
+
+				context("when the uri cannot be parsed", func() {
+					it("returns an error", func() {
+						_, err := transport.Drop(cnbDir, "https://%%%")
+						Expect(err).To(MatchError(ContainSubstring("failed to parse request uri")))
+					})
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,119 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+var majorVersionRe = regexp.MustCompile(`^\d+$`)
+
+type VersionAliasResolver struct{}
+
+func NewVersionAliasResolver() VersionAliasResolver {
+	return VersionAliasResolver{}
+}
+
+// IsVersionAlias reports whether the given version is one of the aliases
+// lts, current or latest, or a bare major version such as 6, rather than a
+// version or version constraint.
+func IsVersionAlias(version string) bool {
+	switch strings.ToLower(version) {
+	case "lts", "current", "latest":
+		return true
+	}
+
+	return majorVersionRe.MatchString(version)
+}
+
+// Resolve returns the concrete version of the dependency with the given id
+// that the given alias refers to:
+//
+//   - latest is the highest version available
+//   - lts is the highest version of a release line with long term support
+//   - current is the highest version of a release line with standard term
+//     support, which .NET used to call the Current release
+//   - a bare major version is the highest version of that major
+//
+// Release lines are the dependency-constraints of buildpack.toml.
+// Pre-releases are only candidates when allowPrerelease is set.
+func (r VersionAliasResolver) Resolve(path, id, alias, stack, arch string, allowPrerelease bool) (string, error) {
+	metadata, err := parseBuildpackMetadata(path)
+	if err != nil {
+		return "", err
+	}
+
+	var match func(*semver.Version) bool
+	switch strings.ToLower(alias) {
+	case "latest":
+		match = func(*semver.Version) bool { return true }
+
+	case "lts", "current":
+		support := "lts"
+		if strings.EqualFold(alias, "current") {
+			support = "sts"
+		}
+
+		var constraints []*semver.Constraints
+		for _, c := range metadata.DependencyConstraints {
+			if c.ID != id || !strings.EqualFold(c.Support, support) {
+				continue
+			}
+
+			constraint, err := semver.NewConstraint(c.Constraint)
+			if err != nil {
+				return "", invalidVersionError{fmt.Errorf("failed to parse dependency constraint %q: %w", c.Constraint, err)}
+			}
+
+			constraints = append(constraints, constraint)
+		}
+
+		match = func(v *semver.Version) bool {
+			for _, constraint := range constraints {
+				if constraint.Check(v) {
+					return true
+				}
+			}
+			return false
+		}
+
+	default:
+		if !majorVersionRe.MatchString(alias) {
+			return "", invalidVersionError{fmt.Errorf("unsupported version alias %q: must be one of lts, current, latest or a major version", alias)}
+		}
+
+		constraint, err := semver.NewConstraint(fmt.Sprintf("%s.*", alias))
+		if err != nil {
+			return "", invalidVersionError{err}
+		}
+
+		match = constraint.Check
+	}
+
+	found, err := findCandidates(metadata.Dependencies, id, stack, arch)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []*semver.Version
+	for _, candidate := range found.candidates {
+		if candidate.version.Prerelease() != "" && !allowPrerelease {
+			continue
+		}
+
+		if match(releaseOf(candidate.version)) {
+			candidates = append(candidates, candidate.version)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", found.noneMatch(fmt.Sprintf("failed to resolve %q dependency version alias %q: no matching versions", id, alias))
+	}
+
+	sort.Sort(semver.Collection(candidates))
+
+	return candidates[len(candidates)-1].Original(), nil
+}
@@ -0,0 +1,180 @@
+package dotnetcoreaspnet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVersionAliasResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cnbDir   string
+		path     string
+		resolver dotnetcoreaspnet.VersionAliasResolver
+	)
+
+	it.Before(func() {
+		var err error
+		cnbDir, err = ioutil.TempDir("", "cnb")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(cnbDir, "buildpack.toml")
+		Expect(ioutil.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "3.1.22"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "5.0.14"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.0.1"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "6.0.2"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["other-stack"]
+  version = "6.0.9"
+
+[[metadata.dependencies]]
+  id = "dotnet-aspnetcore"
+  stacks = ["some-stack"]
+  version = "7.0.0-rc.1"
+
+[[metadata.dependencies]]
+  id = "other-dependency"
+  stacks = ["some-stack"]
+  version = "8.0.0"
+
+[[metadata.dependency-constraints]]
+  constraint = "3.1.*"
+  id = "dotnet-aspnetcore"
+  patches = 2
+  support = "lts"
+
+[[metadata.dependency-constraints]]
+  constraint = "5.0.*"
+  id = "dotnet-aspnetcore"
+  patches = 2
+  support = "sts"
+
+[[metadata.dependency-constraints]]
+  constraint = "6.0.*"
+  id = "dotnet-aspnetcore"
+  patches = 2
+  support = "lts"
+`), 0600)).To(Succeed())
+
+		resolver = dotnetcoreaspnet.NewVersionAliasResolver()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(cnbDir)).To(Succeed())
+	})
+
+	context("IsVersionAlias", func() {
+		it("recognizes the aliases and bare major versions", func() {
+			Expect(dotnetcoreaspnet.IsVersionAlias("lts")).To(BeTrue())
+			Expect(dotnetcoreaspnet.IsVersionAlias("Current")).To(BeTrue())
+			Expect(dotnetcoreaspnet.IsVersionAlias("LATEST")).To(BeTrue())
+			Expect(dotnetcoreaspnet.IsVersionAlias("6")).To(BeTrue())
+		})
+
+		it("does not recognize versions and constraints", func() {
+			Expect(dotnetcoreaspnet.IsVersionAlias("")).To(BeFalse())
+			Expect(dotnetcoreaspnet.IsVersionAlias("6.0")).To(BeFalse())
+			Expect(dotnetcoreaspnet.IsVersionAlias("6.0.*")).To(BeFalse())
+			Expect(dotnetcoreaspnet.IsVersionAlias("6.0.2")).To(BeFalse())
+		})
+	})
+
+	context("Resolve", func() {
+		it("resolves latest to the highest version, skipping pre-releases", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "latest", "some-stack", "amd64", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("6.0.2"))
+		})
+
+		it("resolves lts to the highest version with long term support", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "lts", "some-stack", "amd64", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("6.0.2"))
+		})
+
+		it("resolves current to the highest version with standard term support", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "current", "some-stack", "amd64", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("5.0.14"))
+		})
+
+		it("resolves a major version to the highest version of that major", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "3", "some-stack", "amd64", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("3.1.22"))
+		})
+
+		context("when pre-releases are allowed", func() {
+			it("resolves latest to the highest version, including pre-releases", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "latest", "some-stack", "amd64", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("7.0.0-rc.1"))
+			})
+
+			it("resolves a major version to a pre-release of that major", func() {
+				version, err := resolver.Resolve(path, "dotnet-aspnetcore", "7", "some-stack", "amd64", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("7.0.0-rc.1"))
+			})
+		})
+
+		it("matches the alias case-insensitively", func() {
+			version, err := resolver.Resolve(path, "dotnet-aspnetcore", "LTS", "some-stack", "amd64", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("6.0.2"))
+		})
+
+		context("failure cases", func() {
+			context("when no version matches the alias", func() {
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "7", "some-stack", "amd64", false)
+					Expect(err).To(MatchError(`failed to resolve "dotnet-aspnetcore" dependency version alias "7": no matching versions on "some-stack" stack for the amd64 architecture. Supported versions are: [3.1.22, 5.0.14, 6.0.1, 6.0.2, 7.0.0-rc.1]`))
+				})
+			})
+
+			context("when the alias is not supported", func() {
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "oldest", "some-stack", "amd64", false)
+					Expect(err).To(MatchError(ContainSubstring(`unsupported version alias "oldest"`)))
+				})
+			})
+
+			context("when the buildpack.toml cannot be parsed", func() {
+				it.Before(func() {
+					Expect(ioutil.WriteFile(path, []byte("%%%"), 0600)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := resolver.Resolve(path, "dotnet-aspnetcore", "lts", "some-stack", "amd64", false)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit"
+)
+
+var versionLineRe = regexp.MustCompile(`^v?(\d+)(?:\.(\d+|\*|x|X))?`)
+
+// FindVersionConflicts returns the entries that request a version, when at
+// least two of them require incompatible release lines. Entries are compared
+// by the major and minor version they allow: 6.0.* and 6.0.2 agree, as do 6
+// and 6.0.*, but 5.0.* and 6.0.* do not. Versions that do not pin a release
+// line, such as the lts alias, or a runtimeconfig.json reference that may
+// roll forward onto another major, never conflict. If the entries agree, nil
+// is returned.
+func FindVersionConflicts(entries []packit.BuildpackPlanEntry) []packit.BuildpackPlanEntry {
+	var (
+		versioned []packit.BuildpackPlanEntry
+		lines     []string
+	)
+
+	for _, entry := range entries {
+		version, _ := entry.Metadata["version"].(string)
+
+		line, ok := versionLine(version, RollForwardPolicy(entry))
+		if !ok {
+			continue
+		}
+
+		versioned = append(versioned, entry)
+		lines = append(lines, line)
+	}
+
+	for i := range lines {
+		for j := i + 1; j < len(lines); j++ {
+			if !versionLinesAgree(lines[i], lines[j]) {
+				return versioned
+			}
+		}
+	}
+
+	return nil
+}
+
+// versionLine returns the release line, major or major.minor, that the given
+// version requires under the given roll-forward policy.
+func versionLine(version, rollForward string) (string, bool) {
+	matches := versionLineRe.FindStringSubmatch(strings.TrimSpace(version))
+	if matches == nil {
+		return "", false
+	}
+
+	switch strings.ToLower(rollForward) {
+	case "major", "latestmajor":
+		return "", false
+	case "minor", "latestminor":
+		return matches[1], true
+	}
+
+	if matches[2] == "" || strings.ContainsAny(matches[2], "*xX") {
+		return matches[1], true
+	}
+
+	return fmt.Sprintf("%s.%s", matches[1], matches[2]), true
+}
+
+// describeVersionSources lists the version requested by each of the given
+// entries, e.g. BP_DOTNET_FRAMEWORK_VERSION "5.0.*", some-app.csproj "6.0.*".
+func describeVersionSources(entries []packit.BuildpackPlanEntry) string {
+	var sources []string
+	for _, entry := range entries {
+		source, _ := entry.Metadata["version-source"].(string)
+		version, _ := entry.Metadata["version"].(string)
+		sources = append(sources, fmt.Sprintf("%s %q", source, version))
+	}
+
+	return strings.Join(sources, ", ")
+}
+
+func versionLinesAgree(left, right string) bool {
+	return left == right || strings.HasPrefix(left, right+".") || strings.HasPrefix(right, left+".")
+}
@@ -0,0 +1,103 @@
+package dotnetcoreaspnet_test
+
+import (
+	"os"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/paketo-buildpacks/packit"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVersionConflicts(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		entry = func(source, version string) packit.BuildpackPlanEntry {
+			return packit.BuildpackPlanEntry{
+				Name: "dotnet-aspnetcore",
+				Metadata: map[string]interface{}{
+					"version-source": source,
+					"version":        version,
+				},
+			}
+		}
+	)
+
+	context("FindVersionConflicts", func() {
+		it("returns nothing when the versions share a release line", func() {
+			Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+				entry("BP_DOTNET_FRAMEWORK_VERSION", "6.0.2"),
+				entry("buildpack.yml", "6.0.*"),
+				entry("some-app.csproj", "6.0.*"),
+				entry("RUNTIME_VERSION", "6"),
+			})).To(BeNil())
+		})
+
+		it("returns the versioned entries when the minor versions differ", func() {
+			conflicts := dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+				entry("BP_DOTNET_FRAMEWORK_VERSION", "5.0.*"),
+				entry("some-app.csproj", "6.0.*"),
+				{Name: "dotnet-aspnetcore", Metadata: map[string]interface{}{"self-contained": true}},
+			})
+			Expect(conflicts).To(Equal([]packit.BuildpackPlanEntry{
+				entry("BP_DOTNET_FRAMEWORK_VERSION", "5.0.*"),
+				entry("some-app.csproj", "6.0.*"),
+			}))
+		})
+
+		it("returns the versioned entries when a major version disagrees with a minor version", func() {
+			Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+				entry("BP_DOTNET_FRAMEWORK_VERSION", "7"),
+				entry("some-app.csproj", "6.0.*"),
+			})).To(HaveLen(2))
+		})
+
+		it("ignores versions that do not pin a release line", func() {
+			Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+				entry("BP_DOTNET_FRAMEWORK_VERSION", "lts"),
+				entry("buildpack.yml", ">= 5.0"),
+				entry("some-app.csproj", "6.0.*"),
+			})).To(BeNil())
+		})
+
+		context("when a version may roll forward", func() {
+			it.After(func() {
+				Expect(os.Unsetenv("DOTNET_ROLL_FORWARD")).To(Succeed())
+			})
+
+			it("compares runtimeconfig.json references by major version", func() {
+				Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+					entry("some-app.csproj", "6.1.*"),
+					entry("runtimeconfig.json", "6.0.0"),
+				})).To(BeNil())
+
+				Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+					entry("some-app.csproj", "7.0.*"),
+					entry("runtimeconfig.json", "6.0.0"),
+				})).To(HaveLen(2))
+			})
+
+			it("ignores references that may roll forward onto another major", func() {
+				Expect(os.Setenv("DOTNET_ROLL_FORWARD", "LatestMajor")).To(Succeed())
+
+				Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+					entry("some-app.csproj", "7.0.*"),
+					entry("runtimeconfig.json", "6.0.0"),
+				})).To(BeNil())
+			})
+
+			it("honors the roll-forward policy of the entry", func() {
+				globalJSON := entry("global.json", "6.0.0")
+				globalJSON.Metadata["roll-forward"] = "LatestPatch"
+
+				Expect(dotnetcoreaspnet.FindVersionConflicts([]packit.BuildpackPlanEntry{
+					entry("some-app.csproj", "6.1.*"),
+					globalJSON,
+				})).To(HaveLen(2))
+			})
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package dotnetcoreaspnet
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// VersionSourceProjectFile names the version source of C#, F# and VB project
+// files, whose entries are recorded under the name of the project file.
+const VersionSourceProjectFile = "project-file"
+
+// DefaultVersionSourcePriority is the order in which version sources take
+// precedence when $BP_DOTNET_VERSION_SOURCE_PRIORITY is not set.
+var DefaultVersionSourcePriority = VersionSourcePriority{
+	"RUNTIME_VERSION",
+	"BP_DOTNET_FRAMEWORK_VERSION",
+	"buildpack.yml",
+	"global.json",
+	VersionSourceProjectFile,
+	"runtimeconfig.json",
+}
+
+var projectFileSourceRe = regexp.MustCompile(`.*\.(cs)|(fs)|(vb)proj`)
+
+// VersionSourcePriority lists version sources from highest to lowest
+// priority.
+type VersionSourcePriority []string
+
+// LoadVersionSourcePriority reads the priority from
+// $BP_DOTNET_VERSION_SOURCE_PRIORITY, a comma-separated list of version
+// sources. Sources that are not listed keep their default order, after the
+// listed ones; the number of listed sources is returned along with the
+// priority.
+func LoadVersionSourcePriority() (VersionSourcePriority, int, error) {
+	value, ok := os.LookupEnv("BP_DOTNET_VERSION_SOURCE_PRIORITY")
+	if !ok {
+		return DefaultVersionSourcePriority, 0, nil
+	}
+
+	var priority VersionSourcePriority
+	listed := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		var source string
+		for _, s := range DefaultVersionSourcePriority {
+			if strings.EqualFold(s, name) {
+				source = s
+			}
+		}
+
+		if source == "" {
+			return nil, 0, fmt.Errorf("invalid $BP_DOTNET_VERSION_SOURCE_PRIORITY: unknown version source %q: must be one of %s", name, strings.Join(DefaultVersionSourcePriority, ", "))
+		}
+
+		if listed[source] {
+			return nil, 0, fmt.Errorf("invalid $BP_DOTNET_VERSION_SOURCE_PRIORITY: version source %q is listed more than once", source)
+		}
+
+		listed[source] = true
+		priority = append(priority, source)
+	}
+
+	count := len(priority)
+	for _, source := range DefaultVersionSourcePriority {
+		if !listed[source] {
+			priority = append(priority, source)
+		}
+	}
+
+	return priority, count, nil
+}
+
+// Priorities returns the priorities in the form taken by
+// EntryResolver.Resolve.
+func (p VersionSourcePriority) Priorities() []interface{} {
+	var priorities []interface{}
+	for _, source := range p {
+		if source == VersionSourceProjectFile {
+			priorities = append(priorities, projectFileSourceRe)
+			continue
+		}
+
+		priorities = append(priorities, source)
+	}
+
+	return priorities
+}
@@ -0,0 +1,93 @@
+package dotnetcoreaspnet_test
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	dotnetcoreaspnet "github.com/paketo-buildpacks/dotnet-core-aspnet"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testVersionSourcePriority(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("LoadVersionSourcePriority", func() {
+		it("defaults to the built-in order", func() {
+			priority, listed, err := dotnetcoreaspnet.LoadVersionSourcePriority()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listed).To(Equal(0))
+			Expect(priority).To(Equal(dotnetcoreaspnet.VersionSourcePriority{
+				"RUNTIME_VERSION",
+				"BP_DOTNET_FRAMEWORK_VERSION",
+				"buildpack.yml",
+				"global.json",
+				"project-file",
+				"runtimeconfig.json",
+			}))
+		})
+
+		context("when the priority is set", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_DOTNET_VERSION_SOURCE_PRIORITY", " RuntimeConfig.json, project-file ,")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_VERSION_SOURCE_PRIORITY")).To(Succeed())
+			})
+
+			it("puts the listed sources first, followed by the rest in their default order", func() {
+				priority, listed, err := dotnetcoreaspnet.LoadVersionSourcePriority()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listed).To(Equal(2))
+				Expect(priority).To(Equal(dotnetcoreaspnet.VersionSourcePriority{
+					"runtimeconfig.json",
+					"project-file",
+					"RUNTIME_VERSION",
+					"BP_DOTNET_FRAMEWORK_VERSION",
+					"buildpack.yml",
+					"global.json",
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			it.After(func() {
+				Expect(os.Unsetenv("BP_DOTNET_VERSION_SOURCE_PRIORITY")).To(Succeed())
+			})
+
+			context("when a source is unknown", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_VERSION_SOURCE_PRIORITY", "runtimeconfig.json,package.json")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, _, err := dotnetcoreaspnet.LoadVersionSourcePriority()
+					Expect(err).To(MatchError(`invalid $BP_DOTNET_VERSION_SOURCE_PRIORITY: unknown version source "package.json": must be one of RUNTIME_VERSION, BP_DOTNET_FRAMEWORK_VERSION, buildpack.yml, global.json, project-file, runtimeconfig.json`))
+				})
+			})
+
+			context("when a source is listed twice", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_DOTNET_VERSION_SOURCE_PRIORITY", "buildpack.yml,BUILDPACK.YML")).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, _, err := dotnetcoreaspnet.LoadVersionSourcePriority()
+					Expect(err).To(MatchError(`invalid $BP_DOTNET_VERSION_SOURCE_PRIORITY: version source "buildpack.yml" is listed more than once`))
+				})
+			})
+		})
+	})
+
+	context("Priorities", func() {
+		it("matches project files by name", func() {
+			Expect(dotnetcoreaspnet.VersionSourcePriority{"project-file", "global.json"}.Priorities()).To(Equal([]interface{}{
+				regexp.MustCompile(`.*\.(cs)|(fs)|(vb)proj`),
+				"global.json",
+			}))
+		})
+	})
+}